@@ -0,0 +1,85 @@
+package inbound
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yKanazawa/sendgrid-dev/model/inbound"
+)
+
+// Simulate handles POST /inbound/simulate. It accepts either a raw RFC 822
+// message (Content-Type: message/rfc822, or anything not application/json)
+// or a simplified JSON body, parses it the way SendGrid's Inbound Parse
+// webhook would, and forwards the result as multipart/form-data to
+// SENDGRID_DEV_INBOUND_URL.
+func Simulate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		url := os.Getenv("SENDGRID_DEV_INBOUND_URL")
+		if url == "" {
+			return c.JSON(http.StatusInternalServerError, echo.Map{
+				"error": "SENDGRID_DEV_INBOUND_URL is not configured",
+			})
+		}
+
+		parsed, err := parseRequest(c)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": err.Error()})
+		}
+
+		body, contentType, err := parsed.ToMultipart()
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, echo.Map{"error": err.Error()})
+		}
+
+		resp, err := http.Post(url, contentType, body)
+		if err != nil {
+			return c.JSON(http.StatusBadGateway, echo.Map{"error": err.Error()})
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		return c.JSON(http.StatusOK, echo.Map{
+			"forwarded_to":      url,
+			"downstream_status": resp.StatusCode,
+		})
+	}
+}
+
+func parseRequest(c echo.Context) (inbound.Parsed, error) {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+	if strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		return inbound.ParseJSON(c.Request().Body)
+	}
+
+	raw, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return inbound.Parsed{}, fmt.Errorf("inbound: reading request body: %w", err)
+	}
+	return inbound.ParseRaw(raw)
+}
+
+// Sample handles GET /inbound/simulate/sample, returning a canned payload so
+// callers can see the field shapes without sending a real message first.
+func Sample() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		sample := inbound.Parsed{
+			To:             "support@example.com",
+			From:           "Jane Doe <jane@example.com>",
+			Subject:        "Help with my order",
+			Text:           "Hi, I have a question about order #1234.",
+			HTML:           "<p>Hi, I have a question about order #1234.</p>",
+			Headers:        "To: support@example.com\nFrom: Jane Doe <jane@example.com>\nSubject: Help with my order",
+			Envelope:       `{"to":["support@example.com"],"from":"jane@example.com"}`,
+			Charsets:       `{"to":"UTF-8","from":"UTF-8","subject":"UTF-8","text":"UTF-8","html":"UTF-8"}`,
+			SPF:            "pass",
+			DKIM:           `{@example.com : pass}`,
+			SpamScore:      "0.1",
+			AttachmentInfo: "",
+		}
+		return c.JSON(http.StatusOK, sample)
+	}
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yKanazawa/sendgrid-dev/model/v3/auth"
+)
+
+// apiKeyView is what GET /v3/api_keys reports per key: only the last 4
+// characters of the key's value as its id (a key of 4 characters or fewer is
+// shown whole), never the rest of the secret.
+type apiKeyView struct {
+	APIKeyID string   `json:"api_key_id"`
+	Name     string   `json:"name"`
+	Scopes   []string `json:"scopes"`
+}
+
+// Scopes handles GET /v3/scopes, reporting the scopes of the API key used to
+// authenticate the request.
+func Scopes() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		key, _ := c.Get("api_key").(auth.Key)
+		return c.JSON(http.StatusOK, echo.Map{"scopes": key.Scopes})
+	}
+}
+
+// APIKeys handles GET /v3/api_keys, listing every configured key.
+func APIKeys() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		keys := auth.Default().List()
+		out := make([]apiKeyView, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, apiKeyView{APIKeyID: maskKey(k.Value), Name: k.Name, Scopes: k.Scopes})
+		}
+		return c.JSON(http.StatusOK, echo.Map{"result": out})
+	}
+}
+
+// maskKey reduces a key to its last 4 characters, the way SendGrid's API
+// key listing only ever shows a masked value after creation.
+func maskKey(key string) string {
+	if len(key) <= 4 {
+		return key
+	}
+	return key[len(key)-4:]
+}
@@ -0,0 +1,28 @@
+// Package send wires the /v3/mail/send HTTP route to the model package of
+// the same name: decode the request body, run it through
+// PostRequest.Validate, and relay whatever status/body that produced.
+package send
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	mailsend "github.com/yKanazawa/sendgrid-dev/model/v3/mail"
+)
+
+// PostSend handles POST /v3/mail/send.
+func PostSend() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var postRequest mailsend.PostRequest
+		if err := postRequest.SetPostRequest(c.Request().Body); err != nil {
+			return c.JSON(http.StatusBadRequest, mailsend.GetErrorResponse(err.Error(), nil, nil))
+		}
+
+		status, resp := postRequest.Validate()
+		if status != http.StatusAccepted {
+			return c.JSON(status, resp)
+		}
+		return c.NoContent(status)
+	}
+}
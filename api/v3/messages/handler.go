@@ -0,0 +1,39 @@
+package messages
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yKanazawa/sendgrid-dev/model/v3/messages"
+)
+
+// ListMessages handles GET /v3/messages, SendGrid's Email Activity search.
+// It accepts the same `query` and `limit` parameters as the real endpoint,
+// though ParseQuery only understands a subset of SendGrid's grammar.
+func ListMessages() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q := messages.ParseQuery(c.QueryParam("query"))
+
+		limit := 10
+		if raw := c.QueryParam("limit"); raw != "" {
+			if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+				limit = n
+			}
+		}
+
+		return c.JSON(http.StatusOK, echo.Map{"messages": messages.Default().Search(q, limit)})
+	}
+}
+
+// GetMessage handles GET /v3/messages/:msg_id.
+func GetMessage() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		m, err := messages.Default().Get(c.Param("msg_id"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		return c.JSON(http.StatusOK, m)
+	}
+}
@@ -0,0 +1,106 @@
+package templates
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yKanazawa/sendgrid-dev/model/v3/templates"
+)
+
+type templateRequest struct {
+	Name string `json:"name"`
+}
+
+type versionRequest struct {
+	Name         string `json:"name"`
+	Active       bool   `json:"active"`
+	Subject      string `json:"subject"`
+	HTMLContent  string `json:"html_content"`
+	PlainContent string `json:"plain_content"`
+}
+
+// ListTemplates handles GET /v3/templates.
+func ListTemplates() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, echo.Map{"templates": templates.Default().ListTemplates()})
+	}
+}
+
+// CreateTemplate handles POST /v3/templates.
+func CreateTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req templateRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		return c.JSON(http.StatusCreated, templates.Default().CreateTemplate(req.Name))
+	}
+}
+
+// GetTemplate handles GET /v3/templates/:id.
+func GetTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		t, err := templates.Default().GetTemplate(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		return c.JSON(http.StatusOK, t)
+	}
+}
+
+// DeleteTemplate handles DELETE /v3/templates/:id.
+func DeleteTemplate() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if err := templates.Default().DeleteTemplate(c.Param("id")); err != nil {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// ListVersions handles GET /v3/templates/:id/versions.
+func ListVersions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		versions, err := templates.Default().ListVersions(c.Param("id"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		return c.JSON(http.StatusOK, echo.Map{"versions": versions})
+	}
+}
+
+// CreateVersion handles POST /v3/templates/:id/versions.
+func CreateVersion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req versionRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+
+		v, err := templates.Default().CreateVersion(c.Param("id"), templates.Version{
+			Name:         req.Name,
+			Active:       req.Active,
+			Subject:      req.Subject,
+			HTMLContent:  req.HTMLContent,
+			PlainContent: req.PlainContent,
+			UpdatedAt:    time.Now(),
+		})
+		if err != nil {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		return c.JSON(http.StatusCreated, v)
+	}
+}
+
+// GetVersion handles GET /v3/templates/:id/versions/:version_id.
+func GetVersion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		v, err := templates.Default().GetVersion(c.Param("id"), c.Param("version_id"))
+		if err != nil {
+			return c.JSON(http.StatusNotFound, err.Error())
+		}
+		return c.JSON(http.StatusOK, v)
+	}
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yKanazawa/sendgrid-dev/internal/eventwebhook"
+	webhookmodel "github.com/yKanazawa/sendgrid-dev/model/v3/webhooks"
+)
+
+type testRequest struct {
+	URL string `json:"url" validate:"required"`
+}
+
+// GetSettings handles GET /v3/user/webhooks/event/settings.
+func GetSettings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, webhookmodel.Default())
+	}
+}
+
+// PatchSettings handles PATCH /v3/user/webhooks/event/settings.
+func PatchSettings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		settings := webhookmodel.Default()
+		if err := c.Bind(&settings); err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		webhookmodel.SetDefault(settings)
+		return c.JSON(http.StatusOK, settings)
+	}
+}
+
+// TestWebhook handles POST /v3/user/webhooks/event/test, firing a single
+// canned event at the given URL so callers can confirm it's reachable.
+func TestWebhook() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		var req testRequest
+		if err := c.Bind(&req); err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+		if req.URL == "" {
+			return c.JSON(http.StatusBadRequest, echo.Map{"error": "url is required"})
+		}
+
+		if err := eventwebhook.SendTest(req.URL); err != nil {
+			return c.JSON(http.StatusBadGateway, echo.Map{"error": err.Error()})
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,273 @@
+// Package eventwebhook emulates SendGrid's Event Webhook. After a message is
+// accepted for delivery, Send schedules the same kind of event payloads
+// (processed, delivered, open, click, bounce, dropped, spamreport,
+// unsubscribe) that a real SendGrid account would push to a configured
+// webhook URL, so local consumers can be exercised without waiting on real
+// mail infrastructure.
+package eventwebhook
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/yKanazawa/sendgrid-dev/model/v3/messages"
+	"github.com/yKanazawa/sendgrid-dev/model/v3/webhooks"
+)
+
+// Event is a single Event Webhook payload entry, matching the fields
+// SendGrid includes for delivery and engagement events.
+type Event struct {
+	Email       string `json:"email"`
+	Timestamp   int64  `json:"timestamp"`
+	Event       string `json:"event"`
+	SGEventID   string `json:"sg_event_id"`
+	SGMessageID string `json:"sg_message_id"`
+	Subject     string `json:"subject,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	URL         string `json:"url,omitempty"`
+}
+
+// rates holds the probability, in [0, 1], that a given engagement event is
+// simulated for an accepted send. They're read fresh from the environment on
+// every Send call, the same as the rest of this mock's per-request env
+// lookups, so tests and operators can change them without a restart.
+type rates struct {
+	delivered   float64
+	open        float64
+	click       float64
+	bounce      float64
+	dropped     float64
+	spamReport  float64
+	unsubscribe float64
+}
+
+func ratesFromEnv() rates {
+	return rates{
+		delivered:   envFloat("SENDGRID_DEV_EVENT_DELIVERED_RATE", 1.0),
+		open:        envFloat("SENDGRID_DEV_EVENT_OPEN_RATE", 0.0),
+		click:       envFloat("SENDGRID_DEV_EVENT_CLICK_RATE", 0.0),
+		bounce:      envFloat("SENDGRID_DEV_EVENT_BOUNCE_RATE", 0.0),
+		dropped:     envFloat("SENDGRID_DEV_EVENT_DROPPED_RATE", 0.0),
+		spamReport:  envFloat("SENDGRID_DEV_EVENT_SPAMREPORT_RATE", 0.0),
+		unsubscribe: envFloat("SENDGRID_DEV_EVENT_UNSUBSCRIBE_RATE", 0.0),
+	}
+}
+
+func envFloat(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return fallback
+	}
+	return f
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	ms, err := strconv.Atoi(v)
+	if err != nil {
+		return fallback
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// NewMessageID returns a new sg_message_id, for callers that need to
+// correlate an accepted send across both the Event Webhook and the Message
+// Activity history.
+func NewMessageID() string {
+	return newID()
+}
+
+// Send simulates delivery and engagement for one accepted message to one
+// recipient, identified by its own messageID, on its own goroutine so the
+// HTTP response isn't held up waiting on it. Every simulated event updates
+// the Message Activity history regardless of configuration; it's
+// additionally POSTed to the configured Event Webhook URL, if any.
+func Send(to, subject, messageID string) {
+	settings := webhooks.Default()
+	r := ratesFromEnv()
+	delay := envDuration("SENDGRID_DEV_EVENT_DELAY_MS", 200*time.Millisecond)
+	jitter := envDuration("SENDGRID_DEV_EVENT_JITTER_MS", 0)
+
+	go deliver(settings, r, delay, jitter, to, subject, messageID)
+}
+
+// SendTest posts a single synthetic "processed" event to url, honoring the
+// configured signing key, for /v3/user/webhooks/event/test.
+func SendTest(url string) error {
+	event := Event{
+		Email:       "test@example.com",
+		Timestamp:   time.Now().Unix(),
+		Event:       "processed",
+		SGEventID:   newID(),
+		SGMessageID: newID(),
+		Subject:     "Sendgrid Event Webhook Test",
+	}
+	return post(url, []Event{event})
+}
+
+func deliver(settings webhooks.Settings, r rates, delay, jitter time.Duration, to, subject, messageID string) {
+	time.Sleep(delay + jitterDuration(jitter))
+
+	// What actually "happened" to the message, independent of whether the
+	// Event Webhook is subscribed to each category: the Message Activity
+	// history reflects all of it.
+	events := buildEvents(r, to, subject, messageID)
+	for _, e := range events {
+		messages.Default().RecordEvent(messageID, e.Event, time.Unix(e.Timestamp, 0))
+	}
+
+	if !settings.Enabled || settings.URL == "" {
+		return
+	}
+
+	// Only the categories the account is subscribed to get forwarded.
+	subscribed := make([]Event, 0, len(events))
+	for _, e := range events {
+		if settings.SubscribesTo(e.Event) {
+			subscribed = append(subscribed, e)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+	if err := post(settings.URL, subscribed); err != nil {
+		fmt.Fprintf(os.Stderr, "eventwebhook: posting to %s: %s\n", settings.URL, err.Error())
+	}
+}
+
+func buildEvents(r rates, to, subject, messageID string) []Event {
+	now := time.Now().Unix()
+	var events []Event
+
+	add := func(rate float64, name, reason, url string) {
+		if !roll(rate) {
+			return
+		}
+		events = append(events, Event{
+			Email:       to,
+			Timestamp:   now,
+			Event:       name,
+			SGEventID:   newID(),
+			SGMessageID: messageID,
+			Subject:     subject,
+			Reason:      reason,
+			URL:         url,
+		})
+	}
+
+	add(1.0, "processed", "", "")
+	add(r.delivered, "delivered", "", "")
+	add(r.open, "open", "", "")
+	add(r.click, "click", "", "https://example.com")
+	add(r.bounce, "bounce", "550 5.1.1 unknown user", "")
+	add(r.dropped, "dropped", "Bounced Address", "")
+	add(r.spamReport, "spamreport", "", "")
+	add(r.unsubscribe, "unsubscribe", "", "")
+
+	return events
+}
+
+func roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	return mathrand.Float64() < rate
+}
+
+func jitterDuration(jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	return time.Duration(mathrand.Int63n(int64(jitter)))
+}
+
+func post(url string, events []Event) error {
+	body, err := json.Marshal(events)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if priv, ok := signingKeyFromEnv(); ok {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		sig := sign(priv, ts, body)
+		req.Header.Set("X-Twilio-Email-Event-Webhook-Signature", sig)
+		req.Header.Set("X-Twilio-Email-Event-Webhook-Timestamp", ts)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("event webhook %s returned %s", url, resp.Status)
+	}
+	return nil
+}
+
+// sign reproduces the signature SendGrid's Event Webhook attaches: an Ed25519
+// signature over the request timestamp concatenated with the raw body.
+func sign(priv ed25519.PrivateKey, timestamp string, body []byte) string {
+	payload := append([]byte(timestamp), body...)
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(priv, payload))
+}
+
+// signingKeyFromEnv decodes SENDGRID_DEV_EVENT_WEBHOOK_SIGNING_KEY, a
+// base64-encoded Ed25519 private key (either the 32-byte seed or the full
+// 64-byte key), so downstream code can exercise signature verification. It
+// reports ok=false when no key is configured.
+func signingKeyFromEnv() (ed25519.PrivateKey, bool) {
+	encoded := os.Getenv("SENDGRID_DEV_EVENT_WEBHOOK_SIGNING_KEY")
+	if encoded == "" {
+		return nil, false
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), true
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), true
+	default:
+		return nil, false
+	}
+}
+
+// newID returns a short, practically-unique id, used for both sg_message_id
+// and sg_event_id.
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
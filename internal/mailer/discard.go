@@ -0,0 +1,19 @@
+package mailer
+
+import "github.com/jordan-wright/email"
+
+func init() {
+	Register("discard", newDiscardMailer)
+}
+
+// discardMailer accepts every message without sending it anywhere, useful for
+// load tests or CI runs that only care about the HTTP response.
+type discardMailer struct{}
+
+func newDiscardMailer() (Mailer, error) {
+	return discardMailer{}, nil
+}
+
+func (discardMailer) Send(*email.Email) error {
+	return nil
+}
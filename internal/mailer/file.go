@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jordan-wright/email"
+)
+
+func init() {
+	Register("file", newFileMailer)
+}
+
+// fileMailer writes each message as an RFC 5322 .eml file into a directory,
+// for teams who'd rather inspect mail on disk than run a local SMTP server.
+type fileMailer struct {
+	dir string
+}
+
+func newFileMailer() (Mailer, error) {
+	dir := os.Getenv("SENDGRID_DEV_MAILER_DIR")
+	if dir == "" {
+		dir = "./maildir"
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return fileMailer{dir: dir}, nil
+}
+
+func (m fileMailer) Send(e *email.Email) error {
+	raw, err := e.Bytes()
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d.eml", time.Now().UnixNano())
+	return os.WriteFile(filepath.Join(m.dir, name), raw, 0o644)
+}
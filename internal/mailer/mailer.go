@@ -0,0 +1,68 @@
+// Package mailer abstracts where a composed message actually goes, so the
+// mail/send handler doesn't need to know whether it's hitting real SMTP, a
+// local maildir, a webhook, or the real SendGrid API.
+package mailer
+
+import (
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/jordan-wright/email"
+)
+
+// Mailer delivers a composed message somewhere. Implementations must be safe
+// for concurrent use.
+type Mailer interface {
+	Send(*email.Email) error
+}
+
+// Factory builds a Mailer from the process environment.
+type Factory func() (Mailer, error)
+
+var factories = map[string]Factory{}
+
+// Register adds a Mailer backend under name, so it can be selected via
+// SENDGRID_DEV_MAILER. Backends call this from an init func.
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// New builds the named backend.
+func New(name string) (Mailer, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("mailer: unknown backend %q", name)
+	}
+	return factory()
+}
+
+// NewFromEnv builds the backend selected by SENDGRID_DEV_MAILER, defaulting
+// to "smtp" to match this project's historical behavior.
+func NewFromEnv() (Mailer, error) {
+	name := os.Getenv("SENDGRID_DEV_MAILER")
+	if name == "" {
+		name = "smtp"
+	}
+	return New(name)
+}
+
+var (
+	mu      sync.RWMutex
+	current Mailer
+)
+
+// Default returns the process-wide Mailer, installed at startup by
+// route.Init via SetDefault.
+func Default() Mailer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetDefault overrides the process-wide Mailer.
+func SetDefault(m Mailer) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = m
+}
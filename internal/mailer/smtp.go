@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"net/smtp"
+	"os"
+	"strings"
+
+	"github.com/jordan-wright/email"
+)
+
+func init() {
+	Register("smtp", newSMTPMailer)
+}
+
+// smtpMailer is the original backend: it delivers to SENDGRID_DEV_SMTP_SERVER,
+// optionally authenticating with SENDGRID_DEV_SMTP_USERNAME/PASSWORD.
+type smtpMailer struct{}
+
+func newSMTPMailer() (Mailer, error) {
+	return smtpMailer{}, nil
+}
+
+func (smtpMailer) Send(e *email.Email) error {
+	server := os.Getenv("SENDGRID_DEV_SMTP_SERVER")
+
+	if username := os.Getenv("SENDGRID_DEV_SMTP_USERNAME"); username != "" {
+		host := strings.Split(server, ":")[0]
+		return e.Send(server, smtp.PlainAuth("", username, os.Getenv("SENDGRID_DEV_SMTP_PASSWORD"), host))
+	}
+
+	return e.Send(server, nil)
+}
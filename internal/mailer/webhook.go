@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jordan-wright/email"
+)
+
+func init() {
+	Register("webhook", newWebhookMailer)
+}
+
+// webhookMailer POSTs a JSON envelope describing the message to
+// SENDGRID_DEV_MAILER_WEBHOOK_URL instead of delivering it, so local tooling
+// can react to outbound mail without running an SMTP server.
+type webhookMailer struct {
+	url string
+}
+
+type webhookEnvelope struct {
+	From        string              `json:"from"`
+	To          []string            `json:"to"`
+	Cc          []string            `json:"cc"`
+	Bcc         []string            `json:"bcc"`
+	Subject     string              `json:"subject"`
+	HTML        string              `json:"html"`
+	Text        string              `json:"text"`
+	Attachments []webhookAttachment `json:"attachments,omitempty"`
+}
+
+type webhookAttachment struct {
+	Filename string `json:"filename"`
+	Type     string `json:"type"`
+	Content  string `json:"content"`
+}
+
+func newWebhookMailer() (Mailer, error) {
+	url := os.Getenv("SENDGRID_DEV_MAILER_WEBHOOK_URL")
+	if url == "" {
+		return nil, fmt.Errorf("mailer: SENDGRID_DEV_MAILER_WEBHOOK_URL must be set for the webhook backend")
+	}
+	return webhookMailer{url: url}, nil
+}
+
+func (m webhookMailer) Send(e *email.Email) error {
+	attachments := make([]webhookAttachment, 0, len(e.Attachments))
+	for _, a := range e.Attachments {
+		attachments = append(attachments, webhookAttachment{
+			Filename: a.Filename,
+			Type:     a.ContentType,
+			Content:  base64.StdEncoding.EncodeToString(a.Content),
+		})
+	}
+
+	body, err := json.Marshal(webhookEnvelope{
+		From:        e.From,
+		To:          e.To,
+		Cc:          e.Cc,
+		Bcc:         e.Bcc,
+		Subject:     e.Subject,
+		HTML:        string(e.HTML),
+		Text:        string(e.Text),
+		Attachments: attachments,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.Post(m.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("mailer: webhook %s returned %s", m.url, resp.Status)
+	}
+	return nil
+}
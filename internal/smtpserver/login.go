@@ -0,0 +1,43 @@
+package smtpserver
+
+import (
+	"errors"
+
+	"github.com/emersion/go-sasl"
+)
+
+// loginAuthenticator checks a username/password pair, the LOGIN-mechanism
+// equivalent of sasl.PlainAuthenticator.
+type loginAuthenticator func(username, password string) error
+
+// loginServer implements AUTH LOGIN (github.com/emersion/go-sasl has a
+// client but no server for it): the server prompts for "Username:" then
+// "Password:" as two separate challenges, rather than taking both in one
+// response the way PLAIN does.
+type loginServer struct {
+	authenticate loginAuthenticator
+	username     string
+	step         int
+}
+
+func newLoginServer(authenticator loginAuthenticator) sasl.Server {
+	return &loginServer{authenticate: authenticator}
+}
+
+func (a *loginServer) Next(response []byte) (challenge []byte, done bool, err error) {
+	switch a.step {
+	case 0:
+		a.step++
+		return []byte("Username:"), false, nil
+	case 1:
+		a.username = string(response)
+		a.step++
+		return []byte("Password:"), false, nil
+	case 2:
+		a.step++
+		err = a.authenticate(a.username, string(response))
+		return nil, true, err
+	default:
+		return nil, false, errors.New("sasl: unexpected client response")
+	}
+}
@@ -0,0 +1,86 @@
+// Package smtpserver emulates the receiving side of SendGrid's SMTP relay,
+// so existing SMTP-based SendGrid integrations can point at sendgrid-dev
+// without switching to the HTTP API. It authenticates with AUTH PLAIN or
+// AUTH LOGIN using the username "apikey" and the password against the same
+// auth.Store the HTTP API uses, then translates each message into the same
+// PostRequest JSON /v3/mail/send accepts and runs it through that handler's
+// validation and dispatch path. There is no SMTPS/STARTTLS support: the
+// listener is plaintext only, so point it at a loopback or otherwise
+// trusted network.
+package smtpserver
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/emersion/go-sasl"
+	"github.com/emersion/go-smtp"
+
+	"github.com/yKanazawa/sendgrid-dev/model/v3/auth"
+)
+
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// ListenFromEnv starts the relay listener on SENDGRID_DEV_SMTP_LISTEN in the
+// background and returns immediately. It is a no-op if that variable is
+// unset, so the relay is opt-in.
+func ListenFromEnv() {
+	addr := os.Getenv("SENDGRID_DEV_SMTP_LISTEN")
+	if addr == "" {
+		return
+	}
+
+	server := smtp.NewServer(&backend{})
+	server.Addr = addr
+	server.Domain = "sendgrid-dev"
+	server.AllowInsecureAuth = true
+
+	go func() {
+		fmt.Println("SENDGRID_DEV_SMTP_LISTEN", addr)
+		if err := server.ListenAndServe(); err != nil {
+			log.Printf("smtpserver: %s", err.Error())
+		}
+	}()
+}
+
+// backend hands out a new, unauthenticated session for every connection;
+// the session itself enforces AUTH PLAIN or LOGIN against auth.Default() before
+// accepting MAIL/RCPT/DATA.
+type backend struct{}
+
+func (backend) NewSession(_ *smtp.Conn) (smtp.Session, error) {
+	return &session{}, nil
+}
+
+// AuthMechanisms reports the auth mechanisms session supports.
+func (s *session) AuthMechanisms() []string {
+	return []string{sasl.Plain, sasl.Login}
+}
+
+// Auth authenticates the username "apikey" against auth.Default(), the same
+// store the HTTP API's apiKeyAuth middleware uses, so keys configured via
+// SENDGRID_DEV_API_KEYS_FILE work over SMTP too and need the mail.send
+// scope, same as POST /v3/mail/send. Both AUTH PLAIN and AUTH LOGIN check
+// the same credentials.
+func (s *session) Auth(mech string) (sasl.Server, error) {
+	authenticate := func(username, password string) error {
+		if username != "apikey" {
+			return errInvalidCredentials
+		}
+		key, ok := auth.Default().Lookup(password)
+		if !ok || !key.HasScope("mail.send") {
+			return errInvalidCredentials
+		}
+		s.authenticated = true
+		return nil
+	}
+
+	if mech == sasl.Login {
+		return newLoginServer(authenticate), nil
+	}
+	return sasl.NewPlainServer(func(_, username, password string) error {
+		return authenticate(username, password)
+	}), nil
+}
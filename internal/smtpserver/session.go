@@ -0,0 +1,221 @@
+package smtpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+
+	"github.com/emersion/go-smtp"
+	gomail "github.com/jordan-wright/email"
+
+	mailsend "github.com/yKanazawa/sendgrid-dev/model/v3/mail"
+)
+
+// smtpAPIHeader is SendGrid's SMTP-API header format: clients set the
+// X-SMTPAPI header to this JSON shape to mail-merge one DATA command out to
+// several recipients, each with their own substitutions, instead of sending
+// once per recipient. filters and unique_args are accepted so well-formed
+// clients aren't rejected, but sendgrid-dev has no category/tracking
+// machinery to apply them to.
+type smtpAPIHeader struct {
+	To         []string               `json:"to"`
+	Sub        map[string][]string    `json:"sub"`
+	Filters    map[string]interface{} `json:"filters"`
+	UniqueArgs map[string]string      `json:"unique_args"`
+}
+
+// The following payload* types exist only to be marshaled to JSON and fed
+// through PostRequest.SetPostRequest, so a relayed message runs through the
+// exact same validation and dispatch path as an HTTP /v3/mail/send request.
+type mailPayload struct {
+	Personalizations []personalizationPayload `json:"personalizations"`
+	From             addressPayload           `json:"from"`
+	Subject          string                   `json:"subject"`
+	Content          []contentPayload         `json:"content"`
+}
+
+type personalizationPayload struct {
+	To            []addressPayload  `json:"to"`
+	Cc            []addressPayload  `json:"cc,omitempty"`
+	Bcc           []addressPayload  `json:"bcc,omitempty"`
+	Substitutions map[string]string `json:"substitutions,omitempty"`
+}
+
+type addressPayload struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type contentPayload struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// session handles one SMTP conversation: an AUTH, a MAIL FROM, any number of
+// RCPT TOs, then a single DATA that is translated and dispatched. It rejects
+// MAIL/RCPT/DATA until Auth has succeeded. The envelope RCPT TOs are kept
+// around because a real Bcc recipient never appears in the DATA headers
+// (that's the point of a blind copy) — only in an extra RCPT TO — so
+// toPayload reconciles them against the parsed headers and treats anything
+// missing from there as an implicit Bcc.
+type session struct {
+	authenticated bool
+	from          string
+	rcpts         []string
+}
+
+func (s *session) Mail(from string, _ *smtp.MailOptions) error {
+	if !s.authenticated {
+		return smtp.ErrAuthRequired
+	}
+	s.from = from
+	return nil
+}
+
+func (s *session) Rcpt(to string, _ *smtp.RcptOptions) error {
+	if !s.authenticated {
+		return smtp.ErrAuthRequired
+	}
+	s.rcpts = append(s.rcpts, to)
+	return nil
+}
+
+func (s *session) Reset() {
+	s.from = ""
+	s.rcpts = nil
+}
+
+func (s *session) Logout() error {
+	return nil
+}
+
+func (s *session) Data(r io.Reader) error {
+	if !s.authenticated {
+		return smtp.ErrAuthRequired
+	}
+
+	msg, err := gomail.NewEmailFromReader(r)
+	if err != nil {
+		return fmt.Errorf("smtpserver: parsing message: %w", err)
+	}
+
+	payload, err := toPayload(s.from, s.rcpts, msg)
+	if err != nil {
+		return fmt.Errorf("smtpserver: %w", err)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var postRequest mailsend.PostRequest
+	if err := postRequest.SetPostRequest(io.NopCloser(bytes.NewReader(body))); err != nil {
+		return fmt.Errorf("smtpserver: %w", err)
+	}
+
+	if status, resp := postRequest.Validate(); status != http.StatusAccepted {
+		return fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+	return nil
+}
+
+func toPayload(envelopeFrom string, envelopeRcpts []string, msg *gomail.Email) (mailPayload, error) {
+	payload := mailPayload{
+		From:    fromAddress(envelopeFrom, msg.From),
+		Subject: msg.Subject,
+	}
+	if len(msg.HTML) > 0 {
+		payload.Content = append(payload.Content, contentPayload{Type: "text/html", Value: string(msg.HTML)})
+	}
+	if len(msg.Text) > 0 {
+		payload.Content = append(payload.Content, contentPayload{Type: "text/plain", Value: string(msg.Text)})
+	}
+
+	if raw := msg.Headers.Get("X-Smtpapi"); raw != "" {
+		payload.Personalizations = personalizationsFromSMTPAPI(raw)
+		if payload.Personalizations == nil {
+			return payload, fmt.Errorf("parsing X-SMTPAPI header")
+		}
+		return payload, nil
+	}
+
+	p := personalizationPayload{
+		To:  addresses(msg.To),
+		Cc:  addresses(msg.Cc),
+		Bcc: addresses(msg.Bcc),
+	}
+	addBccRcpts(&p, envelopeRcpts)
+	payload.Personalizations = []personalizationPayload{p}
+	return payload, nil
+}
+
+// addBccRcpts adds envelope RCPT TOs that never showed up in the message's
+// To/Cc/Bcc headers to p.Bcc. A conformant client strips the Bcc header
+// before sending and conveys those recipients only as extra RCPT TOs, so
+// this is how a relayed message recovers them.
+func addBccRcpts(p *personalizationPayload, envelopeRcpts []string) {
+	seen := make(map[string]bool, len(p.To)+len(p.Cc)+len(p.Bcc))
+	for _, addrs := range [][]addressPayload{p.To, p.Cc, p.Bcc} {
+		for _, a := range addrs {
+			seen[a.Email] = true
+		}
+	}
+
+	for _, raw := range envelopeRcpts {
+		addr := parseAddress(raw)
+		if !seen[addr.Email] {
+			p.Bcc = append(p.Bcc, addr)
+			seen[addr.Email] = true
+		}
+	}
+}
+
+func personalizationsFromSMTPAPI(raw string) []personalizationPayload {
+	var api smtpAPIHeader
+	if err := json.Unmarshal([]byte(raw), &api); err != nil {
+		return nil
+	}
+
+	personalizations := make([]personalizationPayload, 0, len(api.To))
+	for i, to := range api.To {
+		p := personalizationPayload{To: []addressPayload{parseAddress(to)}}
+		if len(api.Sub) > 0 {
+			p.Substitutions = make(map[string]string, len(api.Sub))
+			for key, values := range api.Sub {
+				if i < len(values) {
+					p.Substitutions[key] = values[i]
+				}
+			}
+		}
+		personalizations = append(personalizations, p)
+	}
+	return personalizations
+}
+
+func addresses(raw []string) []addressPayload {
+	out := make([]addressPayload, 0, len(raw))
+	for _, r := range raw {
+		out = append(out, parseAddress(r))
+	}
+	return out
+}
+
+func fromAddress(envelopeFrom, headerFrom string) addressPayload {
+	raw := headerFrom
+	if raw == "" {
+		raw = envelopeFrom
+	}
+	return parseAddress(raw)
+}
+
+func parseAddress(raw string) addressPayload {
+	addr, err := mail.ParseAddress(raw)
+	if err != nil {
+		return addressPayload{Email: raw}
+	}
+	return addressPayload{Email: addr.Address, Name: addr.Name}
+}
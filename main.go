@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 
+	"github.com/yKanazawa/sendgrid-dev/internal/smtpserver"
 	"github.com/yKanazawa/sendgrid-dev/route"
 )
 
@@ -39,9 +40,6 @@ func main() {
 	}
 	fmt.Println("SENDGRID_DEV_API_SERVER", os.Getenv("SENDGRID_DEV_API_SERVER"))
 
-	if os.Getenv("SENDGRID_DEV_API_KEY") == "" {
-		os.Setenv("SENDGRID_DEV_API_KEY", "SG.xxxxx")
-	}
 	fmt.Println("SENDGRID_DEV_API_KEY", os.Getenv("SENDGRID_DEV_API_KEY"))
 
 	if os.Getenv("SENDGRID_DEV_SMTP_SERVER") == "" {
@@ -52,5 +50,8 @@ func main() {
 	fmt.Println("SENDGRID_DEV_SMTP_PASSWORD", os.Getenv("SENDGRID_DEV_SMTP_PASSWORD"))
 
 	router := route.Init()
+
+	smtpserver.ListenFromEnv()
+
 	router.Logger.Fatal(router.Start(os.Getenv("SENDGRID_DEV_API_SERVER")))
 }
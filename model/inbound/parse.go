@@ -0,0 +1,239 @@
+// Package inbound reproduces the fields SendGrid's Inbound Parse webhook
+// sends to a receiving app, so that app can be exercised locally without a
+// real inbound MX route.
+package inbound
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+)
+
+// Attachment is a single inbound attachment, keyed the way SendGrid keys them
+// in the multipart body: "attachment1", "attachment2", ...
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"-"`
+}
+
+// Parsed holds the fields SendGrid's Inbound Parse webhook posts for a
+// single received email.
+type Parsed struct {
+	To             string
+	From           string
+	Subject        string
+	Text           string
+	HTML           string
+	Headers        string
+	Envelope       string
+	Charsets       string
+	SPF            string
+	DKIM           string
+	SpamScore      string
+	Attachments    []Attachment
+	AttachmentInfo string
+}
+
+// envelope mirrors the JSON SendGrid embeds in the "envelope" form field.
+type envelope struct {
+	To   []string `json:"to"`
+	From string   `json:"from"`
+}
+
+// jsonRequest is the structured-JSON alternative to posting a raw RFC 822
+// message to /inbound/simulate.
+type jsonRequest struct {
+	To      string `json:"to"`
+	From    string `json:"from"`
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	HTML    string `json:"html"`
+}
+
+// ParseRaw parses a raw RFC 822 message, as net/mail and mime/multipart would
+// see it arrive over SMTP, into the fields SendGrid exposes to consumers.
+func ParseRaw(raw []byte) (Parsed, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return Parsed{}, fmt.Errorf("inbound: parsing message: %w", err)
+	}
+
+	p := Parsed{
+		To:        msg.Header.Get("To"),
+		From:      msg.Header.Get("From"),
+		Subject:   msg.Header.Get("Subject"),
+		Headers:   formatHeaders(msg.Header),
+		SPF:       "none",
+		DKIM:      "none",
+		SpamScore: "0.0",
+	}
+
+	env := envelope{From: addressOnly(p.From)}
+	for _, addr := range strings.Split(p.To, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			env.To = append(env.To, addressOnly(addr))
+		}
+	}
+	if envJSON, err := json.Marshal(env); err == nil {
+		p.Envelope = string(envJSON)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		body, _ := io.ReadAll(msg.Body)
+		p.Text = string(body)
+		p.Charsets = `{"to":"UTF-8","from":"UTF-8","subject":"UTF-8","text":"UTF-8"}`
+		return p, nil
+	}
+
+	charsets := map[string]string{"to": "UTF-8", "from": "UTF-8", "subject": "UTF-8"}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Parsed{}, fmt.Errorf("inbound: reading part: %w", err)
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return Parsed{}, fmt.Errorf("inbound: reading part body: %w", err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch {
+		case part.FileName() != "":
+			p.Attachments = append(p.Attachments, Attachment{
+				Filename:    part.FileName(),
+				ContentType: partType,
+				Content:     data,
+			})
+		case partType == "text/html":
+			p.HTML = string(data)
+			charsets["html"] = "UTF-8"
+		default:
+			p.Text = string(data)
+			charsets["text"] = "UTF-8"
+		}
+	}
+
+	if charsetsJSON, err := json.Marshal(charsets); err == nil {
+		p.Charsets = string(charsetsJSON)
+	}
+
+	if len(p.Attachments) > 0 {
+		info := make(map[string]map[string]string, len(p.Attachments))
+		for i, a := range p.Attachments {
+			info[fmt.Sprintf("attachment%d", i+1)] = map[string]string{
+				"filename": a.Filename,
+				"type":     a.ContentType,
+			}
+		}
+		if infoJSON, err := json.Marshal(info); err == nil {
+			p.AttachmentInfo = string(infoJSON)
+		}
+	}
+
+	return p, nil
+}
+
+// ParseJSON builds a Parsed from the simplified JSON body /inbound/simulate
+// also accepts, for callers that don't want to hand-build a raw message.
+func ParseJSON(body io.Reader) (Parsed, error) {
+	var req jsonRequest
+	if err := json.NewDecoder(body).Decode(&req); err != nil {
+		return Parsed{}, fmt.Errorf("inbound: decoding json body: %w", err)
+	}
+
+	env := envelope{From: addressOnly(req.From)}
+	if req.To != "" {
+		env.To = append(env.To, addressOnly(req.To))
+	}
+	envJSON, _ := json.Marshal(env)
+
+	return Parsed{
+		To:        req.To,
+		From:      req.From,
+		Subject:   req.Subject,
+		Text:      req.Text,
+		HTML:      req.HTML,
+		Headers:   fmt.Sprintf("To: %s\nFrom: %s\nSubject: %s", req.To, req.From, req.Subject),
+		Envelope:  string(envJSON),
+		Charsets:  `{"to":"UTF-8","from":"UTF-8","subject":"UTF-8","text":"UTF-8","html":"UTF-8"}`,
+		SPF:       "none",
+		DKIM:      "none",
+		SpamScore: "0.0",
+	}, nil
+}
+
+// ToMultipart renders p as the multipart/form-data body SendGrid posts to an
+// Inbound Parse receiving URL.
+func (p Parsed) ToMultipart() (*bytes.Buffer, string, error) {
+	buf := new(bytes.Buffer)
+	w := multipart.NewWriter(buf)
+
+	fields := map[string]string{
+		"to":              p.To,
+		"from":            p.From,
+		"subject":         p.Subject,
+		"text":            p.Text,
+		"html":            p.HTML,
+		"headers":         p.Headers,
+		"envelope":        p.Envelope,
+		"charsets":        p.Charsets,
+		"SPF":             p.SPF,
+		"dkim":            p.DKIM,
+		"spam_score":      p.SpamScore,
+		"attachments":     fmt.Sprintf("%d", len(p.Attachments)),
+		"attachment-info": p.AttachmentInfo,
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := w.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for i, a := range p.Attachments {
+		part, err := w.CreateFormFile(fmt.Sprintf("attachment%d", i+1), a.Filename)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := part.Write(a.Content); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf, w.FormDataContentType(), nil
+}
+
+func formatHeaders(h mail.Header) string {
+	var b strings.Builder
+	for key, values := range h {
+		for _, v := range values {
+			fmt.Fprintf(&b, "%s: %s\n", key, v)
+		}
+	}
+	return b.String()
+}
+
+func addressOnly(s string) string {
+	addr, err := mail.ParseAddress(s)
+	if err != nil {
+		return strings.TrimSpace(s)
+	}
+	return addr.Address
+}
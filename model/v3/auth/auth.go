@@ -0,0 +1,142 @@
+// Package auth models the API keys this mock accepts and the scopes each
+// one is allowed to use, the way a real SendGrid account's key management
+// does.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ScopeAll grants every scope. It's what the single env-configured API key
+// gets, matching this mock's historical behavior of accepting any
+// authenticated request.
+const ScopeAll = "*"
+
+// Key is one configured API key: the secret value itself, a display name,
+// and the scopes it's allowed to use.
+type Key struct {
+	Value  string   `json:"api_key"`
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+// HasScope reports whether k is allowed to use scope, honoring ScopeAll.
+func (k Key) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == ScopeAll {
+			return true
+		}
+	}
+	return false
+}
+
+// Store looks up configured API keys. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	Lookup(value string) (Key, bool)
+	List() []Key
+}
+
+// staticStore is a Store over a fixed set of keys, loaded once at startup.
+type staticStore struct {
+	keys map[string]Key
+}
+
+func (s staticStore) Lookup(value string) (Key, bool) {
+	k, ok := s.keys[value]
+	return k, ok
+}
+
+func (s staticStore) List() []Key {
+	out := make([]Key, 0, len(s.keys))
+	for _, k := range s.keys {
+		out = append(out, k)
+	}
+	return out
+}
+
+var (
+	mu      sync.RWMutex
+	current Store = staticStore{}
+)
+
+// Default returns the process-wide API key store, installed at startup by
+// NewFromEnv.
+func Default() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetDefault overrides the process-wide API key store.
+func SetDefault(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = s
+}
+
+// NewFromEnv builds the Store selected by SENDGRID_DEV_API_KEYS_FILE, a JSON
+// or YAML file listing multiple keys with their own scopes. Without it, the
+// mock falls back to a single full-access key taken from
+// SENDGRID_DEV_API_KEY, matching its historical single-key behavior. If
+// that's unset too, a random key is generated and logged rather than
+// falling back to a fixed, guessable default.
+func NewFromEnv() (Store, error) {
+	if path := os.Getenv("SENDGRID_DEV_API_KEYS_FILE"); path != "" {
+		return newFileStore(path)
+	}
+
+	value := os.Getenv("SENDGRID_DEV_API_KEY")
+	if value == "" {
+		value = randomAPIKey()
+		log.Printf("auth: SENDGRID_DEV_API_KEY not set, generated %s", value)
+	}
+	return staticStore{keys: map[string]Key{
+		value: {Value: value, Name: "default", Scopes: []string{ScopeAll}},
+	}}, nil
+}
+
+// randomAPIKey returns an "SG."-prefixed key in the same shape as a real
+// SendGrid API key, unique per process so unconfigured deployments don't
+// share a guessable credential.
+func randomAPIKey() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "SG." + hex.EncodeToString(b)
+}
+
+func newFileStore(path string) (Store, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []Key
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &keys)
+	case ".json":
+		err = json.Unmarshal(data, &keys)
+	default:
+		err = errors.New("auth: unsupported API keys file extension " + ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := staticStore{keys: make(map[string]Key, len(keys))}
+	for _, k := range keys {
+		s.keys[k.Value] = k
+	}
+	return s, nil
+}
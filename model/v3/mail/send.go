@@ -6,13 +6,19 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
-	"net/smtp"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/aymerick/raymond"
 	"github.com/jordan-wright/email"
-	"gopkg.in/go-playground/validator.v9"
+
+	"github.com/yKanazawa/sendgrid-dev/internal/eventwebhook"
+	"github.com/yKanazawa/sendgrid-dev/internal/mailer"
+	"github.com/yKanazawa/sendgrid-dev/model/v3/mail/validation"
+	"github.com/yKanazawa/sendgrid-dev/model/v3/messages"
+	"github.com/yKanazawa/sendgrid-dev/model/v3/templates"
 )
 
 type PostRequest struct {
@@ -53,7 +59,12 @@ type PostRequest struct {
 		Disposition string `json:"disposition"`
 		ContentId   string `json:"content_id"`
 	} `json:"attachments"`
-	TemplateID string `json:"template_id"`
+	TemplateID   string `json:"template_id"`
+	MailSettings struct {
+		SandboxMode struct {
+			Enable bool `json:"enable"`
+		} `json:"sandbox_mode"`
+	} `json:"mail_settings"`
 }
 
 type ErrorResponse struct {
@@ -68,42 +79,63 @@ func (postRequest *PostRequest) SetPostRequest(requestBody io.ReadCloser) error
 	return json.NewDecoder(requestBody).Decode(&postRequest)
 }
 
+// Validate runs postRequest through the validation package's rule table and,
+// if it passes every rule, hands off to sendMailWithSMTP. mail_settings.
+// sandbox_mode.enable=true still runs every rule and builds the full
+// message, only skipping actual dispatch, matching SendGrid's own sandbox
+// behavior.
 func (postRequest *PostRequest) Validate() (int, ErrorResponse) {
-	validate := validator.New()
-	if err := validate.Struct(postRequest); err != nil {
-		for _, err := range err.(validator.ValidationErrors) {
-			switch err.ActualTag() {
-			case "required":
-				switch err.StructField() {
-				case "Personalizations":
-					return http.StatusBadRequest,
-						GetErrorResponse(
-							"The personalizations field is required and must have at least one personalization.",
-							"personalizations",
-							"http://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html#-Personalizations-Errors",
-						)
-				case "Email":
-					return http.StatusBadRequest,
-						GetErrorResponse(
-							"The from object must be provided for every email send. It is an object that requires the email parameter, but may also contain a name parameter.  e.g. {\"email\" : \"example@example.com\"}  or {\"email\" : \"example@example.com\", \"name\" : \"Example Recipient\"}.",
-							"from.email",
-							"http://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html#message.from",
-						)
-				case "Content":
-					return http.StatusBadRequest,
-						GetErrorResponse(
-							"Unless a valid template_id is provided, the content parameter is required. There must be at least one defined content block. We typically suggest both text/plain and text/html blocks are included, but only one block is required.",
-							"content",
-							"http://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html#message.content",
-						)
-				}
-			}
-		}
+	if fieldErr := validation.Validate(postRequest.toValidationRequest()); fieldErr != nil {
+		return http.StatusBadRequest, GetErrorResponse(fieldErr.Message, fieldErr.Field, fieldErr.Help)
 	}
 
 	return sendMailWithSMTP(*postRequest)
 }
 
+func (postRequest *PostRequest) toValidationRequest() validation.Request {
+	req := validation.Request{
+		FromEmail:  postRequest.From.Email,
+		Subject:    postRequest.Subject,
+		TemplateID: postRequest.TemplateID,
+		TemplateExists: func(id string) bool {
+			_, err := templates.Default().ActiveVersion(id)
+			return err == nil
+		},
+		TemplateHasSubject: func(id string) bool {
+			version, err := templates.Default().ActiveVersion(id)
+			return err == nil && version.Subject != ""
+		},
+	}
+
+	for _, p := range postRequest.Personalizations {
+		vp := validation.Personalization{Subject: p.Subject}
+		for _, to := range p.To {
+			vp.To = append(vp.To, validation.Recipient{Email: to.Email})
+		}
+		for _, cc := range p.Cc {
+			vp.Cc = append(vp.Cc, validation.Recipient{Email: cc.Email})
+		}
+		for _, bcc := range p.Bcc {
+			vp.Bcc = append(vp.Bcc, validation.Recipient{Email: bcc.Email})
+		}
+		req.Personalizations = append(req.Personalizations, vp)
+	}
+
+	for _, c := range postRequest.Content {
+		req.Content = append(req.Content, validation.Content{Type: c.Type, Value: c.Value})
+	}
+
+	for _, a := range postRequest.Attachments {
+		req.Attachments = append(req.Attachments, validation.Attachment{
+			Content:     a.Content,
+			Disposition: a.Disposition,
+			ContentID:   a.ContentId,
+		})
+	}
+
+	return req
+}
+
 func GetErrorResponse(message string, field interface{}, help interface{}) ErrorResponse {
 	errorJSON := ErrorResponse{}
 	e := struct {
@@ -147,18 +179,42 @@ func sendMailWithSMTP(postRequest PostRequest) (int, ErrorResponse) {
 
 		if personalizations.Subject != "" {
 			e.Subject = replacer.Replace(personalizations.Subject)
-		} else if postRequest.Subject != "" {
-			e.Subject = replacer.Replace(postRequest.Subject)
 		} else {
-			return http.StatusBadRequest,
-				GetErrorResponse(
-					"The subject is required. You can get around this requirement if you use a template with a subject defined or if every personalization has a subject defined.",
-					"subject",
-					"http://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html#message.subject",
-				)
+			e.Subject = replacer.Replace(postRequest.Subject)
 		}
 		if postRequest.TemplateID != "" && len(personalizations.DynamicTemplateData) > 0 {
-			// TODO
+			version, err := templates.Default().ActiveVersion(postRequest.TemplateID)
+			if err != nil {
+				return http.StatusBadRequest,
+					GetErrorResponse(
+						"template_id provided does not match an existing template with an active version.",
+						"template_id",
+						"http://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html#message.template_id",
+					)
+			}
+
+			if version.Subject != "" {
+				subject, err := raymond.Render(version.Subject, personalizations.DynamicTemplateData)
+				if err != nil {
+					return http.StatusBadRequest,
+						GetErrorResponse(err.Error(), "personalizations.dynamic_template_data", nil)
+				}
+				e.Subject = subject
+			}
+
+			html, err := raymond.Render(version.HTMLContent, personalizations.DynamicTemplateData)
+			if err != nil {
+				return http.StatusBadRequest,
+					GetErrorResponse(err.Error(), "personalizations.dynamic_template_data", nil)
+			}
+			e.HTML = []byte(html)
+
+			plain, err := raymond.Render(version.PlainContent, personalizations.DynamicTemplateData)
+			if err != nil {
+				return http.StatusBadRequest,
+					GetErrorResponse(err.Error(), "personalizations.dynamic_template_data", nil)
+			}
+			e.Text = []byte(plain)
 		} else {
 			for _, content := range postRequest.Content {
 				if content.Type == "text/html" {
@@ -200,24 +256,26 @@ func sendMailWithSMTP(postRequest PostRequest) (int, ErrorResponse) {
 			i++
 		}
 
-		if os.Getenv("SENDGRID_DEV_TEST") == "1" {
+		if os.Getenv("SENDGRID_DEV_TEST") == "1" || postRequest.MailSettings.SandboxMode.Enable {
 			continue
 		}
 
-		if len(os.Getenv("SENDGRID_DEV_SMTP_USERNAME")) > 0 {
-			arr := strings.Split(os.Getenv("SENDGRID_DEV_SMTP_SERVER"), ":")
-			e.Send(
-				os.Getenv("SENDGRID_DEV_SMTP_SERVER"),
-				smtp.PlainAuth(
-					"",
-					os.Getenv("SENDGRID_DEV_SMTP_USERNAME"),
-					os.Getenv("SENDGRID_DEV_SMTP_PASSWORD"),
-					arr[0],
-				),
-			)
+		if err := mailer.Default().Send(e); err != nil {
+			return http.StatusInternalServerError, GetErrorResponse(err.Error(), nil, nil)
 		}
 
-		e.Send(os.Getenv("SENDGRID_DEV_SMTP_SERVER"), nil)
+		for _, to := range personalizations.To {
+			msgID := eventwebhook.NewMessageID()
+			messages.Default().Record(messages.Message{
+				MsgID:         msgID,
+				FromEmail:     postRequest.From.Email,
+				Subject:       e.Subject,
+				ToEmail:       to.Email,
+				Status:        "processed",
+				LastEventTime: time.Now(),
+			})
+			eventwebhook.Send(to.Email, e.Subject, msgID)
+		}
 	}
 	return http.StatusAccepted, GetErrorResponse("", nil, nil)
 }
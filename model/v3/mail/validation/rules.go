@@ -0,0 +1,226 @@
+package validation
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/mail"
+)
+
+const (
+	errorsDocBase = "http://sendgrid.com/docs/API_Reference/Web_API_v3/Mail/errors.html"
+
+	maxRecipients      = 1000
+	maxAttachmentBytes = 30 * 1024 * 1024
+)
+
+var allowedContentTypes = map[string]bool{
+	"text/plain": true,
+	"text/html":  true,
+}
+
+var allowedDispositions = map[string]bool{
+	"":           true, // defaults to "attachment"
+	"inline":     true,
+	"attachment": true,
+}
+
+func fieldError(message string, field, help string) *FieldError {
+	return &FieldError{Message: message, Field: field, Help: help}
+}
+
+func personalizationsRequired(req Request) *FieldError {
+	if len(req.Personalizations) > 0 {
+		return nil
+	}
+	return fieldError(
+		"The personalizations field is required and must have at least one personalization.",
+		"personalizations",
+		errorsDocBase+"#-Personalizations-Errors",
+	)
+}
+
+func fromRequired(req Request) *FieldError {
+	if req.FromEmail != "" {
+		return nil
+	}
+	return fieldError(
+		`The from object must be provided for every email send. It is an object that requires the email parameter, but may also contain a name parameter.  e.g. {"email" : "example@example.com"}  or {"email" : "example@example.com", "name" : "Example Recipient"}.`,
+		"from.email",
+		errorsDocBase+"#message.from",
+	)
+}
+
+func contentRequired(req Request) *FieldError {
+	if len(req.Content) > 0 || req.TemplateID != "" {
+		return nil
+	}
+	return fieldError(
+		"Unless a valid template_id is provided, the content parameter is required. There must be at least one defined content block. We typically suggest both text/plain and text/html blocks are included, but only one block is required.",
+		"content",
+		errorsDocBase+"#message.content",
+	)
+}
+
+func subjectRequired(req Request) *FieldError {
+	if req.TemplateID != "" && req.TemplateHasSubject != nil && req.TemplateHasSubject(req.TemplateID) {
+		return nil
+	}
+	for i, p := range req.Personalizations {
+		if p.Subject != "" || req.Subject != "" {
+			continue
+		}
+		return fieldError(
+			"The subject is required. You can get around this requirement if you use a template with a subject defined or if every personalization has a subject defined.",
+			fmt.Sprintf("personalizations.%d.subject", i),
+			errorsDocBase+"#message.subject",
+		)
+	}
+	return nil
+}
+
+func recipientCount(req Request) *FieldError {
+	total := 0
+	for _, p := range req.Personalizations {
+		total += len(p.To) + len(p.Cc) + len(p.Bcc)
+	}
+	if total <= maxRecipients {
+		return nil
+	}
+	return fieldError(
+		"Personalizations would generate too many messages, or one or more of them have more than 1000 total recipients across to, cc and bcc.",
+		"personalizations",
+		errorsDocBase+"#-Personalizations-Errors",
+	)
+}
+
+func duplicateRecipients(req Request) *FieldError {
+	for i, p := range req.Personalizations {
+		seen := make(map[string]bool, len(p.To)+len(p.Cc)+len(p.Bcc))
+		for _, r := range append(append(append([]Recipient{}, p.To...), p.Cc...), p.Bcc...) {
+			if seen[r.Email] {
+				return fieldError(
+					"A request may not include the same email address in the to, cc, or bcc field.",
+					fmt.Sprintf("personalizations.%d", i),
+					errorsDocBase+"#-Personalizations-Errors",
+				)
+			}
+			seen[r.Email] = true
+		}
+	}
+	return nil
+}
+
+func recipientEmailSyntax(req Request) *FieldError {
+	for i, p := range req.Personalizations {
+		for _, group := range []struct {
+			name string
+			list []Recipient
+		}{{"to", p.To}, {"cc", p.Cc}, {"bcc", p.Bcc}} {
+			for j, r := range group.list {
+				if _, err := mail.ParseAddress(r.Email); err != nil {
+					return fieldError(
+						fmt.Sprintf("The %s email %s is not a valid email address.", group.name, r.Email),
+						fmt.Sprintf("personalizations.%d.%s.%d.email", i, group.name, j),
+						errorsDocBase+"#-Personalizations-Errors",
+					)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func fromEmailSyntax(req Request) *FieldError {
+	if _, err := mail.ParseAddress(req.FromEmail); err != nil {
+		return fieldError(
+			fmt.Sprintf("The from email %s is not a valid email address.", req.FromEmail),
+			"from.email",
+			errorsDocBase+"#message.from",
+		)
+	}
+	return nil
+}
+
+func contentValue(req Request) *FieldError {
+	for i, c := range req.Content {
+		if c.Value != "" {
+			continue
+		}
+		return fieldError(
+			"The content.value field must be a string and is required.",
+			fmt.Sprintf("content.%d.value", i),
+			errorsDocBase+"#message.content",
+		)
+	}
+	return nil
+}
+
+func contentType(req Request) *FieldError {
+	for i, c := range req.Content {
+		if allowedContentTypes[c.Type] {
+			continue
+		}
+		return fieldError(
+			fmt.Sprintf("The content.type field must be a supported content type, such as \"text/plain\" or \"text/html\"; %q is not supported.", c.Type),
+			fmt.Sprintf("content.%d.type", i),
+			errorsDocBase+"#message.content",
+		)
+	}
+	return nil
+}
+
+func templateID(req Request) *FieldError {
+	if req.TemplateID == "" || req.TemplateExists == nil {
+		return nil
+	}
+	if req.TemplateExists(req.TemplateID) {
+		return nil
+	}
+	return fieldError(
+		"template_id provided does not match an existing template with an active version.",
+		"template_id",
+		errorsDocBase+"#message.template_id",
+	)
+}
+
+func attachmentDisposition(req Request) *FieldError {
+	for i, a := range req.Attachments {
+		if allowedDispositions[a.Disposition] {
+			continue
+		}
+		return fieldError(
+			`The disposition value must be either "inline" or "attachment".`,
+			fmt.Sprintf("attachments.%d.disposition", i),
+			errorsDocBase+"#message.attachments.content",
+		)
+	}
+	return nil
+}
+
+func attachmentInlineContentID(req Request) *FieldError {
+	for i, a := range req.Attachments {
+		if a.Disposition != "inline" || a.ContentID != "" {
+			continue
+		}
+		return fieldError(
+			`Attachments with a disposition of "inline" must also specify a content_id.`,
+			fmt.Sprintf("attachments.%d.content_id", i),
+			errorsDocBase+"#message.attachments.content",
+		)
+	}
+	return nil
+}
+
+func attachmentSize(req Request) *FieldError {
+	for i, a := range req.Attachments {
+		if len(a.Content) <= base64.StdEncoding.EncodedLen(maxAttachmentBytes) {
+			continue
+		}
+		return fieldError(
+			"Attachments must be less than 30MB.",
+			fmt.Sprintf("attachments.%d.content", i),
+			errorsDocBase+"#message.attachments.content",
+		)
+	}
+	return nil
+}
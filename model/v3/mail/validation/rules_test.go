@@ -0,0 +1,200 @@
+package validation
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	validReq := Request{
+		Personalizations: []Personalization{{To: []Recipient{{Email: "to@example.com"}}}},
+		FromEmail:        "from@example.com",
+		Subject:          "hello",
+		Content:          []Content{{Type: "text/plain", Value: "body"}},
+	}
+
+	tests := []struct {
+		name      string
+		req       Request
+		wantField string
+	}{
+		{
+			name: "valid request passes every rule",
+			req:  validReq,
+		},
+		{
+			name:      "missing personalizations",
+			req:       withContent(withFrom(Request{}, "from@example.com"), "text/plain", "body"),
+			wantField: "personalizations",
+		},
+		{
+			name:      "missing from",
+			req:       withContent(withPersonalization(Request{}), "text/plain", "body"),
+			wantField: "from.email",
+		},
+		{
+			name:      "missing content and template_id",
+			req:       withFrom(withPersonalization(Request{}), "from@example.com"),
+			wantField: "content",
+		},
+		{
+			name: "missing subject on a personalization and top level",
+			req: withContent(withFrom(Request{
+				Personalizations: []Personalization{{To: []Recipient{{Email: "to@example.com"}}}},
+			}, "from@example.com"), "text/plain", "body"),
+			wantField: "personalizations.0.subject",
+		},
+		{
+			name: "too many total recipients",
+			req: mutate(validReq, func(r *Request) {
+				to := make([]Recipient, maxRecipients+1)
+				for i := range to {
+					to[i] = Recipient{Email: "to@example.com"}
+				}
+				r.Personalizations[0].To = to
+			}),
+			wantField: "personalizations",
+		},
+		{
+			name: "duplicate recipient across to/cc/bcc",
+			req: mutate(validReq, func(r *Request) {
+				r.Personalizations[0].Cc = []Recipient{{Email: "to@example.com"}}
+			}),
+			wantField: "personalizations.0",
+		},
+		{
+			name: "invalid recipient email syntax",
+			req: mutate(validReq, func(r *Request) {
+				r.Personalizations[0].To[0].Email = "not-an-email"
+			}),
+			wantField: "personalizations.0.to.0.email",
+		},
+		{
+			name: "invalid from email syntax",
+			req: mutate(validReq, func(r *Request) {
+				r.FromEmail = "not-an-email"
+			}),
+			wantField: "from.email",
+		},
+		{
+			name: "empty content value",
+			req: mutate(validReq, func(r *Request) {
+				r.Content[0].Value = ""
+			}),
+			wantField: "content.0.value",
+		},
+		{
+			name: "unsupported content type",
+			req: mutate(validReq, func(r *Request) {
+				r.Content[0].Type = "application/json"
+			}),
+			wantField: "content.0.type",
+		},
+		{
+			name: "template_id that doesn't exist",
+			req: mutate(validReq, func(r *Request) {
+				r.TemplateID = "d-missing"
+				r.TemplateExists = func(string) bool { return false }
+			}),
+			wantField: "template_id",
+		},
+		{
+			name: "template_id that exists passes",
+			req: mutate(validReq, func(r *Request) {
+				r.TemplateID = "d-exists"
+				r.TemplateExists = func(string) bool { return true }
+			}),
+		},
+		{
+			name: "no subject anywhere but template supplies one passes",
+			req: withContent(withFrom(Request{
+				Personalizations: []Personalization{{To: []Recipient{{Email: "to@example.com"}}}},
+				TemplateID:       "d-has-subject",
+				TemplateHasSubject: func(string) bool {
+					return true
+				},
+			}, "from@example.com"), "text/plain", "body"),
+		},
+		{
+			name: "no subject anywhere and template has none either fails",
+			req: withContent(withFrom(Request{
+				Personalizations: []Personalization{{To: []Recipient{{Email: "to@example.com"}}}},
+				TemplateID:       "d-no-subject",
+				TemplateHasSubject: func(string) bool {
+					return false
+				},
+			}, "from@example.com"), "text/plain", "body"),
+			wantField: "personalizations.0.subject",
+		},
+		{
+			name: "invalid attachment disposition",
+			req: mutate(validReq, func(r *Request) {
+				r.Attachments = []Attachment{{Disposition: "sideways"}}
+			}),
+			wantField: "attachments.0.disposition",
+		},
+		{
+			name: "inline attachment without content_id",
+			req: mutate(validReq, func(r *Request) {
+				r.Attachments = []Attachment{{Disposition: "inline"}}
+			}),
+			wantField: "attachments.0.content_id",
+		},
+		{
+			name: "attachment over the size limit",
+			req: mutate(validReq, func(r *Request) {
+				oversized := strings.Repeat("a", base64.StdEncoding.EncodedLen(maxAttachmentBytes)+1)
+				r.Attachments = []Attachment{{Content: oversized}}
+			}),
+			wantField: "attachments.0.content",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.req)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %+v, want no error", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Validate() = nil, want error on field %q", tt.wantField)
+			}
+			if err.Field != tt.wantField {
+				t.Errorf("Field = %q, want %q", err.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func withPersonalization(r Request) Request {
+	r.Personalizations = []Personalization{{To: []Recipient{{Email: "to@example.com"}}}}
+	return r
+}
+
+func withFrom(r Request, email string) Request {
+	r.FromEmail = email
+	return r
+}
+
+func withContent(r Request, typ, value string) Request {
+	r.Content = []Content{{Type: typ, Value: value}}
+	return r
+}
+
+// mutate copies r's Personalizations (with their To lists) and Content slices
+// before applying fn, so callers can tweak a shared base Request without the
+// tests aliasing each other's backing arrays. Cc, Bcc and Attachments are not
+// copied.
+func mutate(r Request, fn func(*Request)) Request {
+	r.Personalizations = append([]Personalization{}, r.Personalizations...)
+	for i := range r.Personalizations {
+		r.Personalizations[i].To = append([]Recipient{}, r.Personalizations[i].To...)
+	}
+	r.Content = append([]Content{}, r.Content...)
+	fn(&r)
+	return r
+}
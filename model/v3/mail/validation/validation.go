@@ -0,0 +1,95 @@
+// Package validation implements the checks SendGrid's /v3/mail/send
+// performs on a request before accepting it, as a table of rules evaluated
+// in order. This replaces hand-rolled, ad-hoc error handling with a single
+// place new send-time error cases get added.
+package validation
+
+// FieldError is one errors[] entry, matching the message/field/help tuple
+// SendGrid returns for a failed send.
+type FieldError struct {
+	Message string
+	Field   interface{}
+	Help    interface{}
+}
+
+// Recipient is one addressee in a personalization's to/cc/bcc list.
+type Recipient struct {
+	Email string
+}
+
+// Personalization is the subset of a send's personalization the rules look
+// at.
+type Personalization struct {
+	To      []Recipient
+	Cc      []Recipient
+	Bcc     []Recipient
+	Subject string
+}
+
+// Content is one entry of the top-level `content` array.
+type Content struct {
+	Type  string
+	Value string
+}
+
+// Attachment is one entry of the top-level `attachments` array.
+type Attachment struct {
+	Content     string
+	Disposition string
+	ContentID   string
+}
+
+// Request is the subset of a /v3/mail/send body the rules look at.
+type Request struct {
+	Personalizations []Personalization
+	FromEmail        string
+	Subject          string
+	Content          []Content
+	Attachments      []Attachment
+	TemplateID       string
+	// TemplateExists reports whether TemplateID names a stored template
+	// with an active version. A nil func means template_id is never
+	// checked against the template store.
+	TemplateExists func(id string) bool
+	// TemplateHasSubject reports whether TemplateID's active version
+	// defines its own subject. A nil func means a template is never
+	// treated as supplying a subject.
+	TemplateHasSubject func(id string) bool
+}
+
+// Rule is one table entry: Check returns a FieldError when req fails it, or
+// nil when req passes.
+type Rule struct {
+	Name  string
+	Check func(Request) *FieldError
+}
+
+// Rules is evaluated in order; Validate returns the first failure, the same
+// "stop at the first problem" behavior SendGrid's own API has.
+var Rules = []Rule{
+	{"personalizations_required", personalizationsRequired},
+	{"from_required", fromRequired},
+	{"content_required", contentRequired},
+	{"subject_required", subjectRequired},
+	{"recipient_count", recipientCount},
+	{"duplicate_recipients", duplicateRecipients},
+	{"recipient_email_syntax", recipientEmailSyntax},
+	{"from_email_syntax", fromEmailSyntax},
+	{"content_value", contentValue},
+	{"content_type", contentType},
+	{"template_id", templateID},
+	{"attachment_disposition", attachmentDisposition},
+	{"attachment_inline_content_id", attachmentInlineContentID},
+	{"attachment_size", attachmentSize},
+}
+
+// Validate runs Rules against req in order and returns the first failure, or
+// nil if req passes every rule.
+func Validate(req Request) *FieldError {
+	for _, rule := range Rules {
+		if err := rule.Check(req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package messages
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var messagesBucket = []byte("messages")
+
+// boltStore is a memoryStore that also mirrors every write to a BoltDB file,
+// so the ring buffer's contents survive a restart. Reads are served from
+// memory; Bolt is only consulted at startup and on writes.
+type boltStore struct {
+	*memoryStore
+	db *bolt.DB
+}
+
+// NewBoltStore returns a Store backed by a BoltDB file at path, replaying any
+// previously recorded messages into a size-entry ring buffer on open.
+func NewBoltStore(path string, size int) (Store, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(messagesBucket)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+
+	s := &boltStore{memoryStore: &memoryStore{buf: make([]Message, size), index: make(map[string]int)}, db: db}
+
+	var replayed []Message
+	if err := db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(messagesBucket).ForEach(func(_, v []byte) error {
+			var m Message
+			if err := json.Unmarshal(v, &m); err != nil {
+				return err
+			}
+			replayed = append(replayed, m)
+			return nil
+		})
+	}); err != nil {
+		return nil, err
+	}
+
+	// Bolt's ForEach walks keys (MsgID, a random hex string) in lexicographic
+	// order, not recency. memoryStore.record relies on insertion order for
+	// its "newest first" ring buffer semantics, so sort by LastEventTime
+	// before replaying.
+	sort.Slice(replayed, func(i, j int) bool {
+		return replayed[i].LastEventTime.Before(replayed[j].LastEventTime)
+	})
+	for _, m := range replayed {
+		s.memoryStore.record(m)
+	}
+
+	return s, nil
+}
+
+func (s *boltStore) put(m Message) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(messagesBucket).Put([]byte(m.MsgID), data)
+	})
+}
+
+func (s *boltStore) Record(m Message) {
+	s.memoryStore.Record(m)
+	_ = s.put(m)
+}
+
+func (s *boltStore) RecordEvent(msgID, event string, at time.Time) {
+	s.memoryStore.RecordEvent(msgID, event, at)
+	if m, err := s.memoryStore.Get(msgID); err == nil {
+		_ = s.put(m)
+	}
+}
@@ -0,0 +1,113 @@
+package messages
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryStore is the default Store, keeping the most recent size messages in
+// a ring buffer in process memory; older entries are evicted as new ones
+// arrive.
+type memoryStore struct {
+	mu    sync.RWMutex
+	buf   []Message
+	index map[string]int // msg_id -> slot in buf
+	next  int
+	count int
+}
+
+// NewMemoryStore returns a Store that keeps the last size messages in memory
+// only; its contents are lost when the process exits.
+func NewMemoryStore(size int) Store {
+	return &memoryStore{buf: make([]Message, size), index: make(map[string]int)}
+}
+
+func (s *memoryStore) Record(m Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.record(m)
+}
+
+func (s *memoryStore) record(m Message) {
+	slot := s.next
+	if evicted := s.buf[slot]; evicted.MsgID != "" {
+		delete(s.index, evicted.MsgID)
+	}
+	s.buf[slot] = m
+	s.index[m.MsgID] = slot
+	s.next = (s.next + 1) % len(s.buf)
+	if s.count < len(s.buf) {
+		s.count++
+	}
+}
+
+func (s *memoryStore) Get(msgID string) (Message, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	slot, ok := s.index[msgID]
+	if !ok {
+		return Message{}, ErrNotFound
+	}
+	return s.buf[slot], nil
+}
+
+func (s *memoryStore) RecordEvent(msgID, event string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slot, ok := s.index[msgID]
+	if !ok {
+		return
+	}
+
+	m := &s.buf[slot]
+	switch event {
+	case "open":
+		m.OpensCount++
+	case "click":
+		m.ClicksCount++
+	}
+	if status, ok := statusForEvent(event); ok {
+		m.Status = status
+	}
+	m.LastEventTime = at
+}
+
+// statusForEvent maps an Event Webhook event name to the activity status it
+// should leave a message in; engagement events that don't change delivery
+// status (open, click) report ok=false. RecordEvent bumps LastEventTime for
+// every event either way.
+func statusForEvent(event string) (string, bool) {
+	switch event {
+	case "processed", "delivered", "bounce", "dropped", "deferred":
+		return event, true
+	case "spamreport":
+		return "spam_report", true
+	case "unsubscribe":
+		return "unsubscribe", true
+	default:
+		return "", false
+	}
+}
+
+func (s *memoryStore) Search(q Query, limit int) []Message {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if limit <= 0 {
+		limit = 10
+	}
+
+	out := make([]Message, 0, limit)
+	// Walk newest-first: the slot just written to is s.next-1, and we
+	// unwind count entries from there.
+	for i := 0; i < s.count && len(out) < limit; i++ {
+		slot := (s.next - 1 - i + len(s.buf)) % len(s.buf)
+		m := s.buf[slot]
+		if q.Match(m) {
+			out = append(out, m)
+		}
+	}
+	return out
+}
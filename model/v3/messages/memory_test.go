@@ -0,0 +1,78 @@
+package messages
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreSearchNewestFirst(t *testing.T) {
+	s := NewMemoryStore(3)
+	s.Record(Message{MsgID: "1", ToEmail: "a@example.com"})
+	s.Record(Message{MsgID: "2", ToEmail: "b@example.com"})
+	s.Record(Message{MsgID: "3", ToEmail: "c@example.com"})
+
+	got := s.Search(Query{}, 10)
+	want := []string{"3", "2", "1"}
+	if len(got) != len(want) {
+		t.Fatalf("Search() returned %d messages, want %d", len(got), len(want))
+	}
+	for i, m := range got {
+		if m.MsgID != want[i] {
+			t.Errorf("Search()[%d].MsgID = %q, want %q", i, m.MsgID, want[i])
+		}
+	}
+}
+
+func TestMemoryStoreEvictsOldest(t *testing.T) {
+	s := NewMemoryStore(2)
+	s.Record(Message{MsgID: "1"})
+	s.Record(Message{MsgID: "2"})
+	s.Record(Message{MsgID: "3"})
+
+	if _, err := s.Get("1"); err != ErrNotFound {
+		t.Errorf("Get(%q) err = %v, want ErrNotFound", "1", err)
+	}
+	if _, err := s.Get("3"); err != nil {
+		t.Errorf("Get(%q) err = %v, want nil", "3", err)
+	}
+
+	got := s.Search(Query{}, 10)
+	if len(got) != 2 {
+		t.Fatalf("Search() returned %d messages, want 2", len(got))
+	}
+}
+
+func TestMemoryStoreRecordEvent(t *testing.T) {
+	s := NewMemoryStore(10)
+	s.Record(Message{MsgID: "1", Status: "processed"})
+
+	s.RecordEvent("1", "delivered", time.Now())
+	m, err := s.Get("1")
+	if err != nil {
+		t.Fatalf("Get() err = %v", err)
+	}
+	if m.Status != "delivered" {
+		t.Errorf("Status = %q, want %q", m.Status, "delivered")
+	}
+
+	s.RecordEvent("1", "open", time.Now())
+	s.RecordEvent("1", "click", time.Now())
+	m, _ = s.Get("1")
+	if m.OpensCount != 1 {
+		t.Errorf("OpensCount = %d, want 1", m.OpensCount)
+	}
+	if m.ClicksCount != 1 {
+		t.Errorf("ClicksCount = %d, want 1", m.ClicksCount)
+	}
+	if m.Status != "delivered" {
+		t.Errorf("Status after open/click = %q, want unchanged %q", m.Status, "delivered")
+	}
+}
+
+func TestMemoryStoreRecordEventUnknownMsgID(t *testing.T) {
+	s := NewMemoryStore(10)
+	s.RecordEvent("missing", "delivered", time.Now())
+	if _, err := s.Get("missing"); err != ErrNotFound {
+		t.Errorf("Get() err = %v, want ErrNotFound", err)
+	}
+}
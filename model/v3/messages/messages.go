@@ -0,0 +1,81 @@
+// Package messages is a local surrogate for SendGrid's (paid) Email Activity
+// API: it records every message this mock accepts and lets callers list or
+// search that history the same way GET /v3/messages does.
+package messages
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Message is one entry in the activity history, matching the fields
+// SendGrid's Email Activity API documents per message.
+type Message struct {
+	MsgID         string    `json:"msg_id"`
+	FromEmail     string    `json:"from_email"`
+	Subject       string    `json:"subject"`
+	ToEmail       string    `json:"to_email"`
+	Status        string    `json:"status"`
+	OpensCount    int       `json:"opens_count"`
+	ClicksCount   int       `json:"clicks_count"`
+	LastEventTime time.Time `json:"last_event_time"`
+}
+
+// ErrNotFound is returned by Store lookups when no message matches msg_id.
+var ErrNotFound = errors.New("messages: not found")
+
+// Store records accepted sends and their subsequent events. Implementations
+// must be safe for concurrent use.
+type Store interface {
+	// Record adds a newly accepted send to the history.
+	Record(m Message)
+	// Get returns the message for msgID.
+	Get(msgID string) (Message, error)
+	// RecordEvent updates the message for msgID with a later Event Webhook
+	// event (delivered, open, click, bounce, ...), bumping OpensCount /
+	// ClicksCount and LastEventTime as appropriate.
+	RecordEvent(msgID, event string, at time.Time)
+	// Search returns, newest first, up to limit messages matching q.
+	Search(q Query, limit int) []Message
+}
+
+var (
+	mu      sync.RWMutex
+	current Store = NewMemoryStore(defaultHistorySize)
+)
+
+const defaultHistorySize = 1000
+
+// Default returns the process-wide message history store.
+func Default() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetDefault overrides the process-wide message history store.
+func SetDefault(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = s
+}
+
+// NewStoreFromEnv builds the Store selected by SENDGRID_DEV_HISTORY_SIZE (the
+// ring buffer capacity, default 1000) and SENDGRID_DEV_HISTORY_DB (when set,
+// a BoltDB file the history is also persisted to so it survives restarts).
+func NewStoreFromEnv() (Store, error) {
+	size := defaultHistorySize
+	if raw := os.Getenv("SENDGRID_DEV_HISTORY_SIZE"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			size = n
+		}
+	}
+
+	if path := os.Getenv("SENDGRID_DEV_HISTORY_DB"); path != "" {
+		return NewBoltStore(path, size)
+	}
+	return NewMemoryStore(size), nil
+}
@@ -0,0 +1,97 @@
+package messages
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// condition is a single `field op "value"` clause from a query string.
+type condition struct {
+	field string
+	op    string // "=" or "!="
+	value string
+}
+
+// Query is a parsed `query=` parameter as accepted by SendGrid's Email
+// Activity API: a sequence of quoted field/value conditions joined by AND/OR.
+// This is a deliberately small subset of SendGrid's grammar: no parentheses,
+// and AND/OR are evaluated strictly left to right (no operator precedence).
+type Query struct {
+	conditions []condition
+	ops        []string // ops[i] joins conditions[i] and conditions[i+1]
+}
+
+var queryToken = regexp.MustCompile(`(?i)(\w+)\s*(!=|=)\s*"([^"]*)"|(AND|OR)`)
+
+// ParseQuery parses the `query` parameter of GET /v3/messages, e.g.
+// `to_email="jane@example.com" AND status="delivered"`. An empty raw string
+// yields a Query that matches everything.
+func ParseQuery(raw string) Query {
+	var q Query
+	for _, m := range queryToken.FindAllStringSubmatch(raw, -1) {
+		if m[4] != "" {
+			q.ops = append(q.ops, strings.ToUpper(m[4]))
+			continue
+		}
+		q.conditions = append(q.conditions, condition{
+			field: strings.ToLower(m[1]),
+			op:    m[2],
+			value: m[3],
+		})
+	}
+	return q
+}
+
+// Match reports whether m satisfies q.
+func (q Query) Match(m Message) bool {
+	if len(q.conditions) == 0 {
+		return true
+	}
+
+	result := evalCondition(q.conditions[0], m)
+	for i, op := range q.ops {
+		if i+1 >= len(q.conditions) {
+			break
+		}
+		rhs := evalCondition(q.conditions[i+1], m)
+		if op == "OR" {
+			result = result || rhs
+		} else {
+			result = result && rhs
+		}
+	}
+	return result
+}
+
+func evalCondition(c condition, m Message) bool {
+	actual, ok := fieldValue(c.field, m)
+	if !ok {
+		return false
+	}
+	if c.op == "!=" {
+		return actual != c.value
+	}
+	return actual == c.value
+}
+
+func fieldValue(field string, m Message) (string, bool) {
+	switch field {
+	case "msg_id":
+		return m.MsgID, true
+	case "from_email":
+		return m.FromEmail, true
+	case "to_email":
+		return m.ToEmail, true
+	case "subject":
+		return m.Subject, true
+	case "status":
+		return m.Status, true
+	case "opens_count":
+		return strconv.Itoa(m.OpensCount), true
+	case "clicks_count":
+		return strconv.Itoa(m.ClicksCount), true
+	default:
+		return "", false
+	}
+}
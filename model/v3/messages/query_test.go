@@ -0,0 +1,44 @@
+package messages
+
+import "testing"
+
+func TestQueryMatch(t *testing.T) {
+	m := Message{
+		MsgID:       "msg-1",
+		FromEmail:   "from@example.com",
+		ToEmail:     "to@example.com",
+		Subject:     "hello",
+		Status:      "delivered",
+		OpensCount:  2,
+		ClicksCount: 1,
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  bool
+	}{
+		{"empty query matches everything", "", true},
+		{"single equality match", `to_email="to@example.com"`, true},
+		{"single equality mismatch", `to_email="other@example.com"`, false},
+		{"inequality match", `status!="bounce"`, true},
+		{"inequality mismatch", `status!="delivered"`, false},
+		{"AND both true", `to_email="to@example.com" AND status="delivered"`, true},
+		{"AND one false", `to_email="to@example.com" AND status="bounce"`, false},
+		{"OR one true", `status="bounce" OR status="delivered"`, true},
+		{"OR both false", `status="bounce" OR status="dropped"`, false},
+		{"unknown field never matches", `nonexistent="x"`, false},
+		{"numeric field", `opens_count="2"`, true},
+		{"field name is case-insensitive", `TO_EMAIL="to@example.com"`, true},
+		{"left-to-right evaluation, no precedence", `status="delivered" AND status="bounce" OR status="delivered"`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := ParseQuery(tt.query)
+			if got := q.Match(m); got != tt.want {
+				t.Errorf("ParseQuery(%q).Match(m) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
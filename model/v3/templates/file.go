@@ -0,0 +1,67 @@
+package templates
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// fileStore is a memoryStore that flushes its full contents to a JSON file
+// after every mutation and reloads it (if present) on startup. It trades
+// efficiency for simplicity, which is fine for the volumes a local dev mock
+// sees.
+type fileStore struct {
+	*memoryStore
+	path string
+}
+
+// NewFileStore returns a Store backed by a JSON file at path, creating it on
+// first write if it does not already exist.
+func NewFileStore(path string) (Store, error) {
+	fs := &fileStore{memoryStore: &memoryStore{templates: make(map[string]*Template)}, path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fs, nil
+		}
+		return nil, err
+	}
+
+	var dump map[string]*Template
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, err
+	}
+	fs.memoryStore.templates = dump
+	return fs, nil
+}
+
+func (s *fileStore) flush() error {
+	s.mu.RLock()
+	data, err := json.MarshalIndent(s.templates, "", "  ")
+	s.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
+
+func (s *fileStore) CreateTemplate(name string) Template {
+	t := s.memoryStore.CreateTemplate(name)
+	_ = s.flush()
+	return t
+}
+
+func (s *fileStore) DeleteTemplate(id string) error {
+	if err := s.memoryStore.DeleteTemplate(id); err != nil {
+		return err
+	}
+	return s.flush()
+}
+
+func (s *fileStore) CreateVersion(templateID string, v Version) (Version, error) {
+	created, err := s.memoryStore.CreateVersion(templateID, v)
+	if err != nil {
+		return Version{}, err
+	}
+	return created, s.flush()
+}
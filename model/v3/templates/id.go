@@ -0,0 +1,14 @@
+package templates
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID returns a short, practically-unique id of the form "<prefix>-<hex>".
+// It isn't a real ULID (no time-ordering), just shorthand for "unique local id".
+func newID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + "-" + hex.EncodeToString(b)
+}
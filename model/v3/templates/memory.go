@@ -0,0 +1,122 @@
+package templates
+
+import (
+	"sync"
+)
+
+// memoryStore is the default Store, keeping everything in process memory.
+type memoryStore struct {
+	mu        sync.RWMutex
+	templates map[string]*Template
+}
+
+// NewMemoryStore returns a Store that keeps templates and versions in memory
+// only; its contents are lost when the process exits.
+func NewMemoryStore() Store {
+	return &memoryStore{templates: make(map[string]*Template)}
+}
+
+func (s *memoryStore) CreateTemplate(name string) Template {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t := &Template{ID: newID("d"), Name: name}
+	s.templates[t.ID] = t
+	return *t
+}
+
+func (s *memoryStore) ListTemplates() []Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Template, 0, len(s.templates))
+	for _, t := range s.templates {
+		out = append(out, *t)
+	}
+	return out
+}
+
+func (s *memoryStore) GetTemplate(id string) (Template, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.templates[id]
+	if !ok {
+		return Template{}, ErrNotFound
+	}
+	return *t, nil
+}
+
+func (s *memoryStore) DeleteTemplate(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.templates[id]; !ok {
+		return ErrNotFound
+	}
+	delete(s.templates, id)
+	return nil
+}
+
+func (s *memoryStore) CreateVersion(templateID string, v Version) (Version, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.templates[templateID]
+	if !ok {
+		return Version{}, ErrNotFound
+	}
+
+	v.ID = newID("v")
+	v.TemplateID = templateID
+	if v.Active {
+		for i := range t.Versions {
+			t.Versions[i].Active = false
+		}
+	}
+	t.Versions = append(t.Versions, v)
+	return v, nil
+}
+
+func (s *memoryStore) ListVersions(templateID string) ([]Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.templates[templateID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]Version(nil), t.Versions...), nil
+}
+
+func (s *memoryStore) GetVersion(templateID, versionID string) (Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.templates[templateID]
+	if !ok {
+		return Version{}, ErrNotFound
+	}
+	for _, v := range t.Versions {
+		if v.ID == versionID {
+			return v, nil
+		}
+	}
+	return Version{}, ErrNotFound
+}
+
+func (s *memoryStore) ActiveVersion(templateID string) (Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	t, ok := s.templates[templateID]
+	if !ok {
+		return Version{}, ErrNotFound
+	}
+	for _, v := range t.Versions {
+		if v.Active {
+			return v, nil
+		}
+	}
+	return Version{}, ErrNotFound
+}
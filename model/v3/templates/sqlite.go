@@ -0,0 +1,152 @@
+package templates
+
+import (
+	"database/sql"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteStore persists templates and versions to a SQLite database file,
+// for users who want dynamic templates to survive across restarts without
+// running a separate service.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS templates (
+	id   TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS versions (
+	id            TEXT PRIMARY KEY,
+	template_id   TEXT NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+	name          TEXT NOT NULL,
+	active        INTEGER NOT NULL DEFAULT 0,
+	subject       TEXT NOT NULL DEFAULT '',
+	html_content  TEXT NOT NULL DEFAULT '',
+	plain_content TEXT NOT NULL DEFAULT '',
+	updated_at    DATETIME NOT NULL
+);
+`
+
+// NewSQLiteStore opens (creating if necessary) a SQLite database file at path.
+func NewSQLiteStore(path string) (Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return nil, err
+	}
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) CreateTemplate(name string) Template {
+	t := Template{ID: newID("d"), Name: name}
+	_, _ = s.db.Exec(`INSERT INTO templates (id, name) VALUES (?, ?)`, t.ID, t.Name)
+	return t
+}
+
+func (s *sqliteStore) ListTemplates() []Template {
+	rows, err := s.db.Query(`SELECT id, name FROM templates`)
+	if err != nil {
+		return nil
+	}
+	defer rows.Close()
+
+	var out []Template
+	for rows.Next() {
+		var t Template
+		if err := rows.Scan(&t.ID, &t.Name); err != nil {
+			continue
+		}
+		t.Versions, _ = s.ListVersions(t.ID)
+		out = append(out, t)
+	}
+	return out
+}
+
+func (s *sqliteStore) GetTemplate(id string) (Template, error) {
+	var t Template
+	row := s.db.QueryRow(`SELECT id, name FROM templates WHERE id = ?`, id)
+	if err := row.Scan(&t.ID, &t.Name); err != nil {
+		return Template{}, ErrNotFound
+	}
+	t.Versions, _ = s.ListVersions(id)
+	return t, nil
+}
+
+func (s *sqliteStore) DeleteTemplate(id string) error {
+	res, err := s.db.Exec(`DELETE FROM templates WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNotFound
+	}
+	_, err = s.db.Exec(`DELETE FROM versions WHERE template_id = ?`, id)
+	return err
+}
+
+func (s *sqliteStore) CreateVersion(templateID string, v Version) (Version, error) {
+	if _, err := s.GetTemplate(templateID); err != nil {
+		return Version{}, err
+	}
+
+	v.ID = newID("v")
+	v.TemplateID = templateID
+	if v.Active {
+		if _, err := s.db.Exec(`UPDATE versions SET active = 0 WHERE template_id = ?`, templateID); err != nil {
+			return Version{}, err
+		}
+	}
+	_, err := s.db.Exec(
+		`INSERT INTO versions (id, template_id, name, active, subject, html_content, plain_content, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, CURRENT_TIMESTAMP)`,
+		v.ID, v.TemplateID, v.Name, v.Active, v.Subject, v.HTMLContent, v.PlainContent,
+	)
+	return v, err
+}
+
+func (s *sqliteStore) ListVersions(templateID string) ([]Version, error) {
+	rows, err := s.db.Query(
+		`SELECT id, template_id, name, active, subject, html_content, plain_content, updated_at
+		 FROM versions WHERE template_id = ?`, templateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Version
+	for rows.Next() {
+		var v Version
+		if err := rows.Scan(&v.ID, &v.TemplateID, &v.Name, &v.Active, &v.Subject, &v.HTMLContent, &v.PlainContent, &v.UpdatedAt); err != nil {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
+
+func (s *sqliteStore) GetVersion(templateID, versionID string) (Version, error) {
+	var v Version
+	row := s.db.QueryRow(
+		`SELECT id, template_id, name, active, subject, html_content, plain_content, updated_at
+		 FROM versions WHERE template_id = ? AND id = ?`, templateID, versionID)
+	if err := row.Scan(&v.ID, &v.TemplateID, &v.Name, &v.Active, &v.Subject, &v.HTMLContent, &v.PlainContent, &v.UpdatedAt); err != nil {
+		return Version{}, ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *sqliteStore) ActiveVersion(templateID string) (Version, error) {
+	var v Version
+	row := s.db.QueryRow(
+		`SELECT id, template_id, name, active, subject, html_content, plain_content, updated_at
+		 FROM versions WHERE template_id = ? AND active = 1`, templateID)
+	if err := row.Scan(&v.ID, &v.TemplateID, &v.Name, &v.Active, &v.Subject, &v.HTMLContent, &v.PlainContent, &v.UpdatedAt); err != nil {
+		return Version{}, ErrNotFound
+	}
+	return v, nil
+}
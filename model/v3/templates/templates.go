@@ -0,0 +1,89 @@
+package templates
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Version is a single dynamic template version, mirroring the subject/content
+// pair SendGrid stores per transactional template version.
+type Version struct {
+	ID           string    `json:"id"`
+	TemplateID   string    `json:"template_id"`
+	Name         string    `json:"name"`
+	Active       bool      `json:"active"`
+	Subject      string    `json:"subject"`
+	HTMLContent  string    `json:"html_content"`
+	PlainContent string    `json:"plain_content"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Template is a named collection of versions, at most one of which is active.
+type Template struct {
+	ID       string    `json:"id"`
+	Name     string    `json:"name"`
+	Versions []Version `json:"versions"`
+}
+
+// ErrNotFound is returned by Store lookups when the template or version does not exist.
+var ErrNotFound = errors.New("templates: not found")
+
+// Store persists templates and their versions. Implementations must be safe
+// for concurrent use.
+type Store interface {
+	CreateTemplate(name string) Template
+	ListTemplates() []Template
+	GetTemplate(id string) (Template, error)
+	DeleteTemplate(id string) error
+
+	CreateVersion(templateID string, v Version) (Version, error)
+	ListVersions(templateID string) ([]Version, error)
+	GetVersion(templateID, versionID string) (Version, error)
+	ActiveVersion(templateID string) (Version, error)
+}
+
+var (
+	mu      sync.RWMutex
+	current Store = NewMemoryStore()
+)
+
+// Default returns the process-wide template store, selected at startup by
+// NewStoreFromEnv.
+func Default() Store {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetDefault overrides the process-wide template store. It exists mainly for
+// tests and for route.Init to install the backend chosen via env.
+func SetDefault(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = s
+}
+
+// NewStoreFromEnv builds the Store selected by SENDGRID_DEV_TEMPLATE_BACKEND
+// ("memory", the default; "file", backed by a JSON file at
+// SENDGRID_DEV_TEMPLATE_FILE; "sqlite", backed by a database file at
+// SENDGRID_DEV_TEMPLATE_DB).
+func NewStoreFromEnv() (Store, error) {
+	switch os.Getenv("SENDGRID_DEV_TEMPLATE_BACKEND") {
+	case "file":
+		path := os.Getenv("SENDGRID_DEV_TEMPLATE_FILE")
+		if path == "" {
+			path = "sendgrid-dev-templates.json"
+		}
+		return NewFileStore(path)
+	case "sqlite":
+		path := os.Getenv("SENDGRID_DEV_TEMPLATE_DB")
+		if path == "" {
+			path = "sendgrid-dev-templates.db"
+		}
+		return NewSQLiteStore(path)
+	default:
+		return NewMemoryStore(), nil
+	}
+}
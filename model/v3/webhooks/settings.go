@@ -0,0 +1,103 @@
+// Package webhooks models SendGrid's Event Webhook configuration: the
+// per-account settings surfaced under /v3/user/webhooks/event/settings that
+// control whether event payloads are forwarded and which categories are
+// subscribed.
+package webhooks
+
+import (
+	"os"
+	"sync"
+)
+
+// Settings mirrors the fields SendGrid's
+// GET/PATCH /v3/user/webhooks/event/settings returns: whether the webhook is
+// enabled, the URL to post to, and which event categories are subscribed.
+type Settings struct {
+	Enabled          bool   `json:"enabled"`
+	URL              string `json:"url"`
+	Processed        bool   `json:"processed"`
+	Delivered        bool   `json:"delivered"`
+	Open             bool   `json:"open"`
+	Click            bool   `json:"click"`
+	Bounce           bool   `json:"bounce"`
+	Dropped          bool   `json:"dropped"`
+	Deferred         bool   `json:"deferred"`
+	SpamReport       bool   `json:"spam_report"`
+	Unsubscribe      bool   `json:"unsubscribe"`
+	GroupUnsubscribe bool   `json:"group_unsubscribe"`
+	GroupResubscribe bool   `json:"group_resubscribe"`
+}
+
+// SubscribesTo reports whether event (as named in an Event Webhook payload,
+// e.g. "spamreport") is forwarded under these Settings.
+func (s Settings) SubscribesTo(event string) bool {
+	switch event {
+	case "processed":
+		return s.Processed
+	case "delivered":
+		return s.Delivered
+	case "open":
+		return s.Open
+	case "click":
+		return s.Click
+	case "bounce":
+		return s.Bounce
+	case "dropped":
+		return s.Dropped
+	case "deferred":
+		return s.Deferred
+	case "spamreport":
+		return s.SpamReport
+	case "unsubscribe":
+		return s.Unsubscribe
+	case "group_unsubscribe":
+		return s.GroupUnsubscribe
+	case "group_resubscribe":
+		return s.GroupResubscribe
+	default:
+		return false
+	}
+}
+
+var (
+	mu      sync.RWMutex
+	current = NewSettingsFromEnv()
+)
+
+// Default returns the process-wide Event Webhook settings, installed at
+// startup from the environment and mutable afterwards through
+// /v3/user/webhooks/event/settings.
+func Default() Settings {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// SetDefault overrides the process-wide Event Webhook settings.
+func SetDefault(s Settings) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = s
+}
+
+// NewSettingsFromEnv builds Settings from SENDGRID_DEV_EVENT_WEBHOOK_URL. The
+// webhook starts enabled, with every event category subscribed, as soon as a
+// URL is configured.
+func NewSettingsFromEnv() Settings {
+	url := os.Getenv("SENDGRID_DEV_EVENT_WEBHOOK_URL")
+	return Settings{
+		Enabled:          url != "",
+		URL:              url,
+		Processed:        true,
+		Delivered:        true,
+		Open:             true,
+		Click:            true,
+		Bounce:           true,
+		Dropped:          true,
+		Deferred:         true,
+		SpamReport:       true,
+		Unsubscribe:      true,
+		GroupUnsubscribe: true,
+		GroupResubscribe: true,
+	}
+}
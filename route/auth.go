@@ -0,0 +1,74 @@
+package route
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/yKanazawa/sendgrid-dev/model/v3/auth"
+)
+
+// authKeyContextKey is where apiKeyAuth stashes the matched auth.Key so
+// downstream handlers (e.g. GET /v3/scopes) can report on it.
+const authKeyContextKey = "api_key"
+
+// routeScopes maps "METHOD path" (echo's route pattern, e.g.
+// "POST /v3/mail/send") to the scope required to call it. Routes not listed
+// here only require a valid API key, no particular scope.
+var routeScopes = map[string]string{
+	"POST /v3/mail/send": "mail.send",
+}
+
+// apiKeyAuth validates the Authorization: Bearer header against the
+// configured API key(s) and, for routes listed in routeScopes, enforces
+// that the key carries the required scope. It mirrors the exact error
+// bodies SendGrid's own API returns for auth failures.
+func apiKeyAuth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Path() == "/health" {
+				return next(c)
+			}
+
+			token := bearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if token == "" {
+				return unauthorized(c)
+			}
+
+			key, ok := auth.Default().Lookup(token)
+			if !ok {
+				return unauthorized(c)
+			}
+
+			if scope, required := routeScopes[c.Request().Method+" "+c.Path()]; required {
+				if !key.HasScope(scope) {
+					return forbidden(c)
+				}
+			}
+
+			c.Set(authKeyContextKey, key)
+			return next(c)
+		}
+	}
+}
+
+func bearerToken(header string) string {
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(header, prefix)
+}
+
+func unauthorized(c echo.Context) error {
+	return c.JSON(http.StatusUnauthorized, authError("Unauthorized"))
+}
+
+func forbidden(c echo.Context) error {
+	return c.JSON(http.StatusForbidden, authError("Access forbidden"))
+}
+
+func authError(message string) echo.Map {
+	return echo.Map{"errors": []echo.Map{{"message": message, "field": nil, "help": nil}}}
+}
@@ -1,12 +1,22 @@
 package route
 
 import (
+	"log"
 	"net/http"
 	"os"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/yKanazawa/sendgrid-dev/api/inbound"
+	authapi "github.com/yKanazawa/sendgrid-dev/api/v3/auth"
 	"github.com/yKanazawa/sendgrid-dev/api/v3/mail/send"
+	"github.com/yKanazawa/sendgrid-dev/api/v3/messages"
+	"github.com/yKanazawa/sendgrid-dev/api/v3/templates"
+	"github.com/yKanazawa/sendgrid-dev/api/v3/webhooks"
+	"github.com/yKanazawa/sendgrid-dev/internal/mailer"
+	authmodel "github.com/yKanazawa/sendgrid-dev/model/v3/auth"
+	messagemodel "github.com/yKanazawa/sendgrid-dev/model/v3/messages"
+	templatemodel "github.com/yKanazawa/sendgrid-dev/model/v3/templates"
 )
 
 func Init() *echo.Echo {
@@ -22,13 +32,70 @@ func Init() *echo.Echo {
 		return c.String(http.StatusOK, "OK")
 	})
 
+	store, err := templatemodel.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("templates: %s", err.Error())
+	}
+	templatemodel.SetDefault(store)
+
+	m, err := mailer.NewFromEnv()
+	if err != nil {
+		log.Fatalf("mailer: %s", err.Error())
+	}
+	mailer.SetDefault(m)
+
+	messageStore, err := messagemodel.NewStoreFromEnv()
+	if err != nil {
+		log.Fatalf("messages: %s", err.Error())
+	}
+	messagemodel.SetDefault(messageStore)
+
+	keys, err := authmodel.NewFromEnv()
+	if err != nil {
+		log.Fatalf("auth: %s", err.Error())
+	}
+	authmodel.SetDefault(keys)
+	e.Use(apiKeyAuth())
+
 	// Routes
-	v3 := e.Group("/v3/mail")
+	v3Mail := e.Group("/v3/mail")
+	{
+		v3Mail.POST("/send", send.PostSend())
+	}
+
+	v3Templates := e.Group("/v3/templates")
 	{
-		v3.GET("/send", send.GetSend())
-		v3.POST("/send", send.PostSend())
+		v3Templates.GET("", templates.ListTemplates())
+		v3Templates.POST("", templates.CreateTemplate())
+		v3Templates.GET("/:id", templates.GetTemplate())
+		v3Templates.DELETE("/:id", templates.DeleteTemplate())
+		v3Templates.GET("/:id/versions", templates.ListVersions())
+		v3Templates.POST("/:id/versions", templates.CreateVersion())
+		v3Templates.GET("/:id/versions/:version_id", templates.GetVersion())
 	}
 
+	inboundGroup := e.Group("/inbound/simulate")
+	{
+		inboundGroup.POST("", inbound.Simulate())
+		inboundGroup.GET("/sample", inbound.Sample())
+	}
+
+	eventWebhook := e.Group("/v3/user/webhooks/event")
+	{
+		eventWebhook.GET("/settings", webhooks.GetSettings())
+		eventWebhook.PATCH("/settings", webhooks.PatchSettings())
+		eventWebhook.POST("/test", webhooks.TestWebhook())
+	}
+
+	v3Messages := e.Group("/v3/messages")
+	{
+		v3Messages.GET("", messages.ListMessages())
+		v3Messages.GET("/:msg_id", messages.GetMessage())
+	}
+
+	e.GET("/v3/scopes", authapi.Scopes())
+	e.GET("/v3/api_keys", authapi.APIKeys())
+
 	return e
 }
 